@@ -0,0 +1,49 @@
+package automapper
+
+// TypedMapper is a narrow typed mapping interface, which services may depend on instead of *Mapper.
+type TypedMapper[From, To any] interface {
+	Map(from From) (To, error)
+	MapSlice(from []From) ([]To, error)
+	MapInto(from *From, to *To) error
+}
+
+// Adapter maps From values into To values with configured Mapper, e.g.
+//
+//	users := automapper.NewAdapter[User, UserDTO](m)
+//	dto, err := users.Map(user)
+//
+// Options apply to every call of the adapter.
+type Adapter[From, To any] struct {
+	m    *Mapper
+	opts []Option
+}
+
+var _ TypedMapper[struct{}, struct{}] = (*Adapter[struct{}, struct{}])(nil)
+
+// NewAdapter returns adapter mapping with m, which must not be nil.
+func NewAdapter[From, To any](m *Mapper, opts ...Option) *Adapter[From, To] {
+	return &Adapter[From, To]{m: m, opts: opts}
+}
+
+// Map maps from into new To value.
+func (a *Adapter[From, To]) Map(from From) (To, error) {
+	var to To
+	err := a.m.Map(&from, &to, a.opts...)
+	return to, err
+}
+
+// MapSlice maps from elements into new slice, nil slices are mapped into nil.
+func (a *Adapter[From, To]) MapSlice(from []From) ([]To, error) {
+	if from == nil {
+		return nil, nil
+	}
+
+	var to []To
+	err := a.m.Map(&from, &to, a.opts...)
+	return to, err
+}
+
+// MapInto maps from into existing to value like Mapper.Map.
+func (a *Adapter[From, To]) MapInto(from *From, to *To) error {
+	return a.m.Map(from, to, a.opts...)
+}
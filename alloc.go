@@ -0,0 +1,90 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AllocationHook is called whenever mapper allocates pointer destination, before it's mapped into.
+// ptr is the allocated pointer, e.g. *Address, path is a path of destination field,
+// e.g. "Orders[0].Address", and parent is a pointer to struct holding the field,
+// so that hooks can set defaults, e.g. tenant IDs, or back-references to parents.
+type AllocationHook func(ptr, parent interface{}, path string)
+
+// SetAllocationHook sets hook called on allocations of pointer destinations.
+// Mapping with hook tracks destination paths, which makes it slower.
+func (m *Mapper) SetAllocationHook(hook AllocationHook) {
+	m.allocHook = hook
+}
+
+// allocate returns pointer to value of tp taken from its pool, see pooled, or to new value like newValue,
+// passing it to allocation hook.
+func (m *Mapper) allocate(st *mapState, tp reflect.Type) reflect.Value {
+	ptr, ok := m.pooled(tp)
+	if !ok {
+		ptr = m.newValue(tp)
+	}
+
+	if m.allocHook == nil {
+		return ptr
+	}
+
+	var parent interface{}
+	if st.parent.IsValid() {
+		parent = st.parent.Interface()
+	}
+
+	m.allocHook(ptr.Interface(), parent, formatPath(st.path))
+	return ptr
+}
+
+// tracksPath reports whether path of destination being mapped is tracked,
+// which it is if allocations are hooked or changes are tracked, see MapTrack.
+func (m *Mapper) tracksPath(st *mapState) bool {
+	return m.allocHook != nil || st.changes != nil
+}
+
+// enterPath appends segment to path of destination being mapped, if it's tracked.
+func (m *Mapper) enterPath(st *mapState, segment string) {
+	if m.tracksPath(st) {
+		st.path = append(st.path, segment)
+	}
+}
+
+// enterIndex appends element segment, e.g. "[0]" or "[key]", to path like enterPath.
+// Callers check tracksPath first, so that indexes aren't formatted without need.
+func (m *Mapper) enterIndex(st *mapState, index interface{}) {
+	st.path = append(st.path, fmt.Sprintf("[%v]", index))
+}
+
+// leavePath removes the last segment of path added by enterPath.
+func (m *Mapper) leavePath(st *mapState) {
+	if m.tracksPath(st) {
+		st.path = st.path[:len(st.path)-1]
+	}
+}
+
+// enterStruct sets addressable struct to as parent of its fields, returning the previous parent.
+func (m *Mapper) enterStruct(st *mapState, to reflect.Value) reflect.Value {
+	parent := st.parent
+	if m.allocHook != nil && to.CanAddr() {
+		st.parent = to.Addr()
+	}
+
+	return parent
+}
+
+// formatPath joins path segments the same way error paths are joined, e.g. "Items[0].Name".
+func formatPath(segments []string) string {
+	var path strings.Builder
+	for i, segment := range segments {
+		if i > 0 && !strings.HasPrefix(segment, "[") {
+			path.WriteByte('.')
+		}
+
+		path.WriteString(segment)
+	}
+
+	return path.String()
+}
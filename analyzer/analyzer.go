@@ -0,0 +1,328 @@
+// Package analyzer contains analysis.Analyzer verifying automapper mappings at build time.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const mapperPath = "github.com/lebedevars/automapper"
+
+// Analyzer finds Mapper.Map(&A{}, &B{}) calls and reports destination fields which can't be mapped
+// with converters registered with Mapper.Set in the same package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "automapper",
+	Doc:      "reports automapper Map calls with destination fields missing converters",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+type converter struct {
+	from, to types.Type
+}
+
+// checker checks mappings of one package.
+type checker struct {
+	converters []converter
+	visited    map[string]bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected inspect result %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	var sets, maps []*ast.CallExpr
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		switch mapperMethod(pass, call) {
+		case "Set":
+			sets = append(sets, call)
+		case "Map":
+			maps = append(maps, call)
+		}
+	})
+
+	c := &checker{}
+	for _, call := range sets {
+		if len(call.Args) != 1 {
+			continue
+		}
+
+		sig, ok := pass.TypesInfo.TypeOf(call.Args[0]).Underlying().(*types.Signature)
+		if ok && sig.Params().Len() == 1 && sig.Results().Len() > 0 {
+			c.converters = append(c.converters, converter{from: sig.Params().At(0).Type(), to: sig.Results().At(0).Type()})
+		}
+	}
+
+	for _, call := range maps {
+		if len(call.Args) < 2 {
+			continue
+		}
+
+		from, okFrom := pass.TypesInfo.TypeOf(call.Args[0]).Underlying().(*types.Pointer)
+		to, okTo := pass.TypesInfo.TypeOf(call.Args[1]).Underlying().(*types.Pointer)
+		if !okFrom || !okTo {
+			continue
+		}
+
+		c.visited = make(map[string]bool)
+		for _, problem := range c.check(from.Elem(), to.Elem(), "") {
+			pass.Reportf(call.Pos(), "automapper: %s", problem)
+		}
+	}
+
+	return nil, nil
+}
+
+// mapperMethod returns name of automapper.Mapper method called, or empty string.
+func mapperMethod(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	recv := pass.TypesInfo.TypeOf(sel.X)
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != mapperPath || named.Obj().Name() != "Mapper" {
+		return ""
+	}
+
+	return sel.Sel.Name
+}
+
+// check returns problems of mapping from into to, mirroring Mapper strategies.
+func (c *checker) check(from, to types.Type, path string) []string {
+	if c.hasConverter(from, to) || isSelfMapping(from, to) || isUnion(from, to) || isAtomic(from, to) || types.Identical(from, to) || isJSONNumber(from, to) {
+		return nil
+	}
+
+	fromStruct, okFrom := structOf(from)
+	toStruct, okTo := structOf(to)
+	if okFrom && okTo {
+		return c.checkStructs(from, to, fromStruct, toStruct, path)
+	}
+
+	fromSlice, okFrom := from.Underlying().(*types.Slice)
+	toSlice, okTo := to.Underlying().(*types.Slice)
+	if okFrom && okTo {
+		return c.check(fromSlice.Elem(), toSlice.Elem(), path)
+	}
+
+	fromArray, okFrom := from.Underlying().(*types.Array)
+	toArray, okTo := to.Underlying().(*types.Array)
+	if okFrom && okTo && fromArray.Len() == toArray.Len() {
+		return c.check(fromArray.Elem(), toArray.Elem(), path)
+	}
+
+	fromMap, okFrom := from.Underlying().(*types.Map)
+	toMap, okTo := to.Underlying().(*types.Map)
+	if okFrom && okTo {
+		return append(c.check(fromMap.Key(), toMap.Key(), path), c.check(fromMap.Elem(), toMap.Elem(), path)...)
+	}
+
+	fromPtr, okFrom := from.Underlying().(*types.Pointer)
+	toPtr, okTo := to.Underlying().(*types.Pointer)
+	switch {
+	case okFrom && okTo:
+		return c.check(fromPtr.Elem(), toPtr.Elem(), path)
+	case okFrom:
+		return c.check(fromPtr.Elem(), to, path)
+	case okTo:
+		return c.check(from, toPtr.Elem(), path)
+	}
+
+	return []string{fmt.Sprintf("field %s: missing converter %s -> %s", path, from, to)}
+}
+
+func (c *checker) checkStructs(from, to types.Type, fromStruct, toStruct *types.Struct, path string) []string {
+	key := types.TypeString(from, nil) + " -> " + types.TypeString(to, nil)
+	if c.visited[key] {
+		return nil
+	}
+
+	c.visited[key] = true
+	fromFields := make(map[string]int)
+	for i := 0; i < fromStruct.NumFields(); i++ {
+		field := fromStruct.Field(i)
+		if name, opts := parseTag(field, fromStruct.Tag(i)); field.Exported() && !isIgnored(name, opts) {
+			fromFields[name] = i
+		}
+	}
+
+	var problems []string
+	for i := 0; i < toStruct.NumFields(); i++ {
+		toField := toStruct.Field(i)
+		name, opts := parseTag(toField, toStruct.Tag(i))
+		if !toField.Exported() || isIgnored(name, opts) {
+			continue
+		}
+
+		fromIndex, ok := fromFields[name]
+		if !ok {
+			continue
+		}
+
+		fromField := fromStruct.Field(fromIndex)
+		if isEpoch(fromField.Type(), toField.Type(), fromStruct.Tag(fromIndex), toStruct.Tag(i)) {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		problems = append(problems, c.check(fromField.Type(), toField.Type(), fieldPath)...)
+	}
+
+	return problems
+}
+
+func (c *checker) hasConverter(from, to types.Type) bool {
+	for _, conv := range c.converters {
+		if types.Identical(conv.from, from) && types.Identical(conv.to, to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSelfMapping reports whether from implements automapper.MapperTo or to implements automapper.MapperFrom.
+func isSelfMapping(from, to types.Type) bool {
+	return hasMethod(types.NewPointer(from), "MapTo") || hasMethod(types.NewPointer(to), "MapFrom")
+}
+
+// isUnion reports whether from implements automapper.Union or to implements automapper.UnionSetter.
+// Union members are known at runtime only, so such mappings are trusted.
+func isUnion(from, to types.Type) bool {
+	if ptr, ok := from.Underlying().(*types.Pointer); ok {
+		from = ptr.Elem()
+	}
+
+	return hasMethod(types.NewPointer(from), "UnionValue") || hasMethod(types.NewPointer(to), "SetUnionValue")
+}
+
+// isAtomic reports whether values are loaded from or stored into sync/atomic type.
+func isAtomic(from, to types.Type) bool {
+	return isAtomicType(from) != isAtomicType(to)
+}
+
+func isAtomicType(tp types.Type) bool {
+	if ptr, ok := tp.Underlying().(*types.Pointer); ok {
+		tp = ptr.Elem()
+	}
+
+	named, ok := tp.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "sync/atomic" && hasMethod(types.NewPointer(tp), "Load")
+}
+
+func hasMethod(tp types.Type, name string) bool {
+	methods := types.NewMethodSet(tp)
+	for i := 0; i < methods.Len(); i++ {
+		if methods.At(i).Obj().Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func structOf(tp types.Type) (*types.Struct, bool) {
+	if ptr, ok := tp.Underlying().(*types.Pointer); ok {
+		tp = ptr.Elem()
+	}
+
+	st, ok := tp.Underlying().(*types.Struct)
+	return st, ok
+}
+
+// isEpoch reports whether field is mapped between time.Time and int or int64
+// with `mapper:",unix"` or `mapper:",unixms"` tag option of either field.
+func isEpoch(from, to types.Type, fromTag, toTag string) bool {
+	if !(isTime(from) && isEpochInt(to)) && !(isTime(to) && isEpochInt(from)) {
+		return false
+	}
+
+	for _, tag := range []string{fromTag, toTag} {
+		if _, opts := parseTag(nil, tag); hasOption(opts, "unix") || hasOption(opts, "unixms") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTime(tp types.Type) bool {
+	return isNamed(tp, "time", "Time")
+}
+
+// isJSONNumber reports whether json.Number is parsed into numeric or string destination.
+func isJSONNumber(from, to types.Type) bool {
+	basic, ok := to.Underlying().(*types.Basic)
+	return ok && isNamed(from, "encoding/json", "Number") && !isNamed(to, "time", "Duration") &&
+		basic.Info()&(types.IsNumeric|types.IsString) != 0 && basic.Info()&types.IsComplex == 0
+}
+
+func isNamed(tp types.Type, pkg, name string) bool {
+	named, ok := tp.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkg && named.Obj().Name() == name
+}
+
+func isEpochInt(tp types.Type) bool {
+	basic, ok := tp.Underlying().(*types.Basic)
+	return ok && (basic.Kind() == types.Int || basic.Kind() == types.Int64)
+}
+
+// parseTag mirrors automapper tag parsing: `mapper:"name,opt1,opt2=value"`,
+// tag may consist of options only, e.g. `mapper:"csv=3"`. Options are returned without values.
+func parseTag(field *types.Var, tag string) (name string, opts []string) {
+	parts := strings.Split(reflect.StructTag(tag).Get("mapper"), ",")
+	name, values := parts[0], parts[1:]
+	if strings.Contains(name, "=") {
+		name, values = "", parts
+	}
+
+	for _, opt := range values {
+		opt, _, _ = strings.Cut(opt, "=")
+		opts = append(opts, opt)
+	}
+
+	if name == "" && field != nil {
+		name = field.Name()
+	}
+
+	return name, opts
+}
+
+// isIgnored reports whether field tagged `mapper:"-"` or `mapper:",ignore"` is excluded from mapping.
+func isIgnored(name string, opts []string) bool {
+	return name == "-" || hasOption(opts, "ignore")
+}
+
+func hasOption(opts []string, name string) bool {
+	for _, opt := range opts {
+		if opt == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package a
+
+import "github.com/lebedevars/automapper"
+
+type Nested1 struct {
+	Value int
+}
+
+type Nested2 struct {
+	Value string
+}
+
+type Money int
+
+func (m Money) MapTo(dst interface{}) error { return nil }
+
+type From struct {
+	Price  Money
+	ID     int
+	Name   string
+	Count  int `mapper:"total"`
+	Nested []Nested1
+	Lookup *map[string]int
+}
+
+type To struct {
+	Price  string
+	ID     string
+	Name   string
+	Total  float64 `mapper:"total"`
+	Nested []*Nested2
+	Lookup map[string]string
+}
+
+func mapWithoutConverters() {
+	m := automapper.New()
+	_ = m.Map(&From{}, &To{}) // want `field ID: missing converter int -> string` `field total: missing converter int -> float64` `field Nested.Value: missing converter int -> string` `field Lookup: missing converter int -> string`
+}
+
+type FromIgnored struct {
+	ID     string
+	Secret int `mapper:"-"`
+	Token  int `mapper:",ignore"`
+	Notes  int `mapper:"csv=3,ignore"`
+}
+
+type ToIgnored struct {
+	ID     string
+	Secret string `mapper:"-"`
+	Token  string
+	Notes  string
+}
+
+func mapIgnoredFields() {
+	m := automapper.New()
+	_ = m.Map(&FromIgnored{}, &ToIgnored{})
+}
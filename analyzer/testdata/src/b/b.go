@@ -0,0 +1,56 @@
+package b
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/lebedevars/automapper"
+)
+
+type Nested1 struct {
+	Value int
+}
+
+type Nested2 struct {
+	Value string
+}
+
+type UnionNullString struct {
+	String    string
+	UnionType int
+}
+
+func (u *UnionNullString) UnionValue() interface{} {
+	return u.String
+}
+
+type From struct {
+	ID      int
+	Name    string
+	Count   int `mapper:"total"`
+	Nested  []Nested1
+	Created time.Time `mapper:",unix"`
+	Amount  json.Number
+	Email   *UnionNullString
+	Hits    atomic.Int64
+}
+
+type To struct {
+	ID      string
+	Name    string
+	Total   float64 `mapper:"total"`
+	Nested  []*Nested2
+	Created int64
+	Amount  float64
+	Email   string
+	Hits    int64
+}
+
+func mapWithConverters() {
+	m := automapper.New()
+	_ = m.Set(strconv.Itoa)
+	_ = m.Set(func(i int) float64 { return float64(i) })
+	_ = m.Map(&From{}, &To{})
+}
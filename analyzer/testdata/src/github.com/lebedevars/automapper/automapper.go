@@ -0,0 +1,10 @@
+// Package automapper is a stub of the real package for analyzer tests.
+package automapper
+
+type Mapper struct{}
+
+func New() *Mapper { return &Mapper{} }
+
+func (m *Mapper) Set(converter interface{}) error { return nil }
+
+func (m *Mapper) Map(from, to interface{}) error { return nil }
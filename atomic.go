@@ -0,0 +1,77 @@
+package automapper
+
+import (
+	"reflect"
+)
+
+// atomicPkgPath is a path of package, which types are loaded and stored by atomics strategy.
+const atomicPkgPath = "sync/atomic"
+
+// atomicValueType returns type of values loaded from and stored into sync/atomic type tp,
+// e.g. int64 of atomic.Int64, or bool of atomic.Bool.
+func atomicValueType(tp reflect.Type) (reflect.Type, bool) {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp.PkgPath() != atomicPkgPath || tp.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	load, ok := reflect.PointerTo(tp).MethodByName("Load")
+	if !ok || load.Type.NumIn() != 1 || load.Type.NumOut() != 1 {
+		return nil, false
+	}
+
+	store, ok := reflect.PointerTo(tp).MethodByName("Store")
+	if !ok || store.Type.NumIn() != 2 || store.Type.In(1) != load.Type.Out(0) {
+		return nil, false
+	}
+
+	return load.Type.Out(0), true
+}
+
+// isAtomicMapping reports whether exactly one of types is a sync/atomic type.
+func isAtomicMapping(fromType, toType reflect.Type) bool {
+	_, fromAtomic := atomicValueType(fromType)
+	_, toAtomic := atomicValueType(toType)
+	return fromAtomic != toAtomic
+}
+
+// mapAtomicsFunc maps value loaded from sync/atomic source,
+// or stores source mapped into value of sync/atomic destination.
+func (m *Mapper) mapAtomicsFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if _, ok := atomicValueType(fromVal.Type()); ok {
+		if fromVal.Kind() != reflect.Ptr {
+			if !fromVal.CanAddr() {
+				ptr := reflect.New(fromVal.Type())
+				ptr.Elem().Set(fromVal)
+				fromVal = ptr.Elem()
+			}
+
+			fromVal = fromVal.Addr()
+		}
+
+		if fromVal.IsNil() {
+			return nil
+		}
+
+		return m.mapValue(st, fromVal.MethodByName("Load").Call(nil)[0], toVal)
+	}
+
+	valueType, _ := atomicValueType(toVal.Type())
+	value := reflect.New(valueType).Elem()
+	err := m.mapValue(st, fromVal, value)
+	if err != nil {
+		return err
+	}
+
+	if toVal.Kind() != reflect.Ptr {
+		toVal = toVal.Addr()
+	} else if toVal.IsNil() {
+		toVal.Set(reflect.New(toVal.Type().Elem()))
+	}
+
+	toVal.MethodByName("Store").Call([]reflect.Value{value})
+	return nil
+}
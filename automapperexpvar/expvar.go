@@ -0,0 +1,17 @@
+// Package automapperexpvar publishes Mapper stats with expvar. It's a separate package,
+// so that programs using automapper without it don't get /debug/vars registered on http.DefaultServeMux.
+package automapperexpvar
+
+import (
+	"expvar"
+
+	"github.com/lebedevars/automapper"
+)
+
+// Publish publishes Stats of m as expvar variable with name, so that they are served
+// at /debug/vars. Like expvar.Publish, it panics if name is already in use.
+func Publish(name string, m *automapper.Mapper) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return m.Stats()
+	}))
+}
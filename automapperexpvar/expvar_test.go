@@ -0,0 +1,30 @@
+package automapperexpvar_test
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/automapperexpvar"
+)
+
+type Simple struct {
+	Int int
+}
+
+type SimpleDTO struct {
+	Int int64
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithNumericConversion())
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, m.Map(&Simple{Int: i}, &SimpleDTO{}))
+	}
+
+	automapperexpvar.Publish("automapper_test_stats", m)
+	assert.Contains(t, expvar.Get("automapper_test_stats").String(), `"PlanHits":2`)
+}
@@ -0,0 +1,293 @@
+// Package automappertest contains helpers for testing mappings of Mapper.
+package automappertest
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/lebedevars/automapper"
+)
+
+// maxFixtureDepth limits nesting of populated structs, so that fixtures of recursive types are finite.
+const maxFixtureDepth = 4
+
+// TestingT is a subset of testing.TB used by assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Report lists fields, which mapping doesn't cover.
+type Report struct {
+	// Unmapped are paths of destination fields left zero, e.g. "Address.City".
+	Unmapped []string
+	// Unused are paths of source fields, which values don't affect destination.
+	Unused []string
+}
+
+// Covered reports whether every destination field is mapped and every source field is used.
+func (r Report) Covered() bool {
+	return len(r.Unmapped) == 0 && len(r.Unused) == 0
+}
+
+// Coverage maps fully populated fixture of from into value of type of to, reporting destination
+// fields left zero and source fields, which zeroing doesn't change destination. Zero fields of from,
+// including fields of nested structs, slices and maps of one element, are populated with non-zero values,
+// e.g. "1" or 1, so that from may hold values parsed by converters, e.g. Coverage(m, User{Birthday: "2000-01-01"}, UserDTO{}).
+// Arguments may be values or pointers.
+func Coverage(m *automapper.Mapper, from, to interface{}, opts ...automapper.Option) (Report, error) {
+	fixture := populate(reflect.ValueOf(from))
+	toType := indirectType(reflect.TypeOf(to))
+	mapped, err := mapFixture(m, fixture, toType, opts)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{}
+	collectUnmapped(mapped, "", &report.Unmapped)
+	for _, leaf := range sourceLeaves(fixture, "", nil) {
+		zeroed, err := mapFixture(m, zeroField(fixture, leaf.index), toType, opts)
+		if err != nil {
+			return Report{}, fmt.Errorf("error in mapping without %s: %w", leaf.path, err)
+		}
+
+		if reflect.DeepEqual(zeroed.Interface(), mapped.Interface()) {
+			report.Unused = append(report.Unused, leaf.path)
+		}
+	}
+
+	return report, nil
+}
+
+// AssertCoverage asserts that mapping of from into to covers every field, see Coverage.
+func AssertCoverage(t TestingT, m *automapper.Mapper, from, to interface{}, opts ...automapper.Option) bool {
+	t.Helper()
+	report, err := Coverage(m, from, to, opts...)
+	if err != nil {
+		t.Errorf("error in Coverage: %v", err)
+		return false
+	}
+
+	if len(report.Unmapped) > 0 {
+		t.Errorf("unmapped destination fields of %T: %v", to, report.Unmapped)
+	}
+
+	if len(report.Unused) > 0 {
+		t.Errorf("unused source fields of %T: %v", from, report.Unused)
+	}
+
+	return report.Covered()
+}
+
+func indirectType(tp reflect.Type) reflect.Type {
+	if tp.Kind() == reflect.Ptr {
+		return tp.Elem()
+	}
+
+	return tp
+}
+
+// mapFixture maps fixture into new value of toType.
+func mapFixture(m *automapper.Mapper, fixture reflect.Value, toType reflect.Type, opts []automapper.Option) (reflect.Value, error) {
+	from := reflect.New(fixture.Type())
+	from.Elem().Set(fixture)
+	to := reflect.New(toType)
+	err := m.Map(from.Interface(), to.Interface(), opts...)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("error in Map: %w", err)
+	}
+
+	return to.Elem(), nil
+}
+
+// populate returns copy of val, which zero fields are populated.
+func populate(val reflect.Value) reflect.Value {
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	fixture := reflect.New(val.Type()).Elem()
+	fixture.Set(val)
+	fill(fixture, 0)
+	return fixture
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fill sets non-zero values into zero val and its zero fields and elements.
+func fill(val reflect.Value, depth int) {
+	if depth > maxFixtureDepth {
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		if val.Type() == timeType {
+			if val.IsZero() {
+				val.Set(reflect.ValueOf(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+			}
+
+			return
+		}
+
+		for i := 0; i < val.NumField(); i++ {
+			if val.Type().Field(i).IsExported() {
+				fill(val.Field(i), depth+1)
+			}
+		}
+	case reflect.Ptr:
+		if val.IsNil() && depth < maxFixtureDepth {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+
+		if !val.IsNil() {
+			fill(val.Elem(), depth)
+		}
+	case reflect.Slice:
+		if val.Len() == 0 {
+			val.Set(reflect.MakeSlice(val.Type(), 1, 1))
+		}
+
+		for i := 0; i < val.Len(); i++ {
+			fill(val.Index(i), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			fill(val.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if val.Len() > 0 {
+			return
+		}
+
+		key, elem := reflect.New(val.Type().Key()).Elem(), reflect.New(val.Type().Elem()).Elem()
+		fill(key, depth+1)
+		fill(elem, depth+1)
+		val.Set(reflect.MakeMap(val.Type()))
+		val.SetMapIndex(key, elem)
+	default:
+		if val.IsZero() {
+			fillScalar(val)
+		}
+	}
+}
+
+// fillScalar sets 1 or its equivalent into val.
+func fillScalar(val reflect.Value) {
+	switch {
+	case val.Kind() == reflect.Bool:
+		val.SetBool(true)
+	case val.Kind() == reflect.String:
+		val.SetString("1")
+	case val.CanInt():
+		val.SetInt(1)
+	case val.CanUint():
+		val.SetUint(1)
+	case val.CanFloat():
+		val.SetFloat(1)
+	case val.CanComplex():
+		val.SetComplex(1)
+	}
+}
+
+// sourceLeaf is a field of fixture, which isn't struct or pointer to struct, index is its path of field indexes.
+type sourceLeaf struct {
+	path  string
+	index []int
+}
+
+func sourceLeaves(val reflect.Value, prefix string, index []int) []sourceLeaf {
+	var leaves []sourceLeaf
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path, fieldIndex := joinPath(prefix, field.Name), append(index[:len(index):len(index)], i)
+		if nested, ok := nestedStruct(val.Field(i)); ok {
+			leaves = append(leaves, sourceLeaves(nested, path, fieldIndex)...)
+			continue
+		}
+
+		if !val.Field(i).IsZero() {
+			leaves = append(leaves, sourceLeaf{path: path, index: fieldIndex})
+		}
+	}
+
+	return leaves
+}
+
+// nestedStruct returns struct held by val, which fields are reported instead of val.
+func nestedStruct(val reflect.Value) (reflect.Value, bool) {
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct || val.Type() == timeType {
+		return reflect.Value{}, false
+	}
+
+	for i := 0; i < val.NumField(); i++ {
+		if val.Type().Field(i).IsExported() {
+			return val, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// zeroField returns copy of fixture with field at index zeroed, structs pointed on the way are copied,
+// so that fixture isn't changed.
+func zeroField(fixture reflect.Value, index []int) reflect.Value {
+	copied := reflect.New(fixture.Type()).Elem()
+	copied.Set(fixture)
+	val := copied
+	for i, fieldIndex := range index {
+		field := val.Field(fieldIndex)
+		if i == len(index)-1 {
+			field.Set(reflect.Zero(field.Type()))
+			break
+		}
+
+		if field.Kind() == reflect.Ptr {
+			ptr := reflect.New(field.Type().Elem())
+			ptr.Elem().Set(field.Elem())
+			field.Set(ptr)
+			field = ptr.Elem()
+		}
+
+		val = field
+	}
+
+	return copied
+}
+
+// collectUnmapped appends paths of zero fields of struct val to unmapped.
+func collectUnmapped(val reflect.Value, prefix string, unmapped *[]string) {
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := joinPath(prefix, field.Name)
+		if nested, ok := nestedStruct(val.Field(i)); ok {
+			collectUnmapped(nested, path, unmapped)
+			continue
+		}
+
+		if val.Field(i).IsZero() {
+			*unmapped = append(*unmapped, path)
+		}
+	}
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
@@ -0,0 +1,83 @@
+package automappertest_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/automappertest"
+)
+
+type Address struct {
+	City string
+	Zip  string
+}
+
+type User struct {
+	ID      int
+	Name    string
+	Email   string
+	Created time.Time
+	Address *Address
+	Tags    []string
+}
+
+type AddressDTO struct {
+	City string
+}
+
+type UserDTO struct {
+	ID      int
+	Name    string
+	Phone   string
+	Created time.Time
+	Address AddressDTO
+	Tags    []string
+}
+
+// recorder records failures of assertions.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCoverage(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	report, err := automappertest.Coverage(m, User{}, &UserDTO{})
+	assert.NoError(t, err)
+	assert.Equal(t, automappertest.Report{Unmapped: []string{"Phone"}, Unused: []string{"Email", "Address.Zip"}}, report)
+	assert.False(t, report.Covered())
+
+	report, err = automappertest.Coverage(m, User{}, User{})
+	assert.NoError(t, err)
+	assert.True(t, report.Covered())
+
+	_, err = automappertest.Coverage(m, User{}, struct{ ID time.Duration }{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+func TestAssertCoverage(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	r := &recorder{}
+	assert.False(t, automappertest.AssertCoverage(r, m, User{}, UserDTO{}))
+	assert.Equal(t, []string{
+		"unmapped destination fields of automappertest_test.UserDTO: [Phone]",
+		"unused source fields of automappertest_test.User: [Email Address.Zip]",
+	}, r.errors)
+
+	r = &recorder{}
+	assert.True(t, automappertest.AssertCoverage(r, m, Address{}, Address{}))
+	assert.Empty(t, r.errors)
+}
@@ -0,0 +1,214 @@
+package automappertest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/lebedevars/automapper"
+)
+
+// Option configures equality of AssertRoundTrip.
+type Option func(*equality)
+
+// equality compares values semantically.
+type equality struct {
+	timeRounding   time.Duration
+	nilEqualsEmpty bool
+	ignored        map[string]bool
+	mapOpts        []automapper.Option
+}
+
+// WithTimeRounding makes times equal if they are equal after rounding to d, e.g. time.Microsecond
+// for times stored in databases. Times are compared with time.Time.Equal, so that locations are ignored.
+func WithTimeRounding(d time.Duration) Option {
+	return func(e *equality) {
+		e.timeRounding = d
+	}
+}
+
+// WithNilEqualsEmpty makes nil slices and maps equal to empty ones.
+func WithNilEqualsEmpty() Option {
+	return func(e *equality) {
+		e.nilEqualsEmpty = true
+	}
+}
+
+// WithIgnoredFields makes fields with paths, e.g. "Address.City", not compared.
+func WithIgnoredFields(paths ...string) Option {
+	return func(e *equality) {
+		for _, path := range paths {
+			e.ignored[path] = true
+		}
+	}
+}
+
+// WithMapOptions passes opts to mappings.
+func WithMapOptions(opts ...automapper.Option) Option {
+	return func(e *equality) {
+		e.mapOpts = append(e.mapOpts, opts...)
+	}
+}
+
+// AssertRoundTrip asserts that populated fixture of from, see Coverage, mapped into value of type of to
+// and back equals the fixture. If to can't be mapped back, fields of to are compared with source fields
+// of the same names and types instead. Arguments may be values or pointers, e.g.
+//
+//	automappertest.AssertRoundTrip(t, m, User{}, UserDTO{}, automappertest.WithTimeRounding(time.Millisecond))
+func AssertRoundTrip(t TestingT, m *automapper.Mapper, from, to interface{}, opts ...Option) bool {
+	t.Helper()
+	eq := &equality{ignored: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(eq)
+	}
+
+	fixture := populate(reflect.ValueOf(from))
+	mapped, err := mapFixture(m, fixture, indirectType(reflect.TypeOf(to)), eq.mapOpts)
+	if err != nil {
+		t.Errorf("error in mapping %T into %T: %v", from, to, err)
+		return false
+	}
+
+	var diffs []string
+	back, err := mapFixture(m, mapped, fixture.Type(), eq.mapOpts)
+	switch {
+	case errors.Is(err, automapper.ErrMissingConverter) || errors.Is(err, automapper.ErrUnsupportedMapping):
+		eq.diffFields(fixture, mapped, "", &diffs)
+	case err != nil:
+		t.Errorf("error in mapping %T back into %T: %v", to, from, err)
+		return false
+	default:
+		eq.diff(fixture, back, "", &diffs)
+	}
+
+	if len(diffs) > 0 {
+		t.Errorf("mapping of %T into %T isn't equivalent:\n%s", from, to, strings.Join(diffs, "\n"))
+		return false
+	}
+
+	return true
+}
+
+// diffFields compares fields of struct to with source fields of struct from of the same names and types.
+func (e *equality) diffFields(from, to reflect.Value, prefix string, diffs *[]string) {
+	for i := 0; i < to.NumField(); i++ {
+		toField := to.Type().Field(i)
+		fromField, ok := from.Type().FieldByName(toField.Name)
+		path := joinPath(prefix, toField.Name)
+		if !ok || !toField.IsExported() || len(fromField.Index) != 1 || e.ignored[path] {
+			continue
+		}
+
+		fromVal, toVal := from.Field(fromField.Index[0]), to.Field(i)
+		if fromVal.Type() == toVal.Type() {
+			e.diff(fromVal, toVal, path, diffs)
+			continue
+		}
+
+		fromNested, fromOK := nestedStruct(fromVal)
+		toNested, toOK := nestedStruct(toVal)
+		if fromOK && toOK {
+			e.diffFields(fromNested, toNested, path, diffs)
+		}
+	}
+}
+
+// diff appends differences of values of the same type to diffs.
+func (e *equality) diff(a, b reflect.Value, path string, diffs *[]string) {
+	if e.ignored[path] {
+		return
+	}
+
+	if a.Type() == timeType {
+		if !e.equalTimes(a.Interface().(time.Time), b.Interface().(time.Time)) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", pathName(path), a, b))
+		}
+
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() {
+				e.diff(a.Field(i), b.Field(i), joinPath(path, a.Type().Field(i).Name), diffs)
+			}
+		}
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", pathName(path), a, b))
+			}
+
+			return
+		}
+
+		e.diff(a.Elem(), b.Elem(), path, diffs)
+	case reflect.Slice, reflect.Array:
+		if a.Len() == 0 && b.Len() == 0 {
+			e.diffEmpty(a, b, path, diffs)
+			return
+		}
+
+		if a.Len() != b.Len() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length %d != %d", pathName(path), a.Len(), b.Len()))
+			return
+		}
+
+		for i := 0; i < a.Len(); i++ {
+			e.diff(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), diffs)
+		}
+	case reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			e.diffEmpty(a, b, path, diffs)
+			return
+		}
+
+		if a.Len() != b.Len() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length %d != %d", pathName(path), a.Len(), b.Len()))
+			return
+		}
+
+		iter := a.MapRange()
+		for iter.Next() {
+			elemPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+			if elem := b.MapIndex(iter.Key()); elem.IsValid() {
+				e.diff(iter.Value(), elem, elemPath, diffs)
+			} else {
+				*diffs = append(*diffs, elemPath+": missing")
+			}
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", pathName(path), a, b))
+		}
+	}
+}
+
+func (e *equality) equalTimes(a, b time.Time) bool {
+	if e.timeRounding > 0 {
+		a, b = a.Round(e.timeRounding), b.Round(e.timeRounding)
+	}
+
+	return a.Equal(b)
+}
+
+// diffEmpty appends difference of empty slices or maps, one of which is nil, unless nil equals empty.
+func (e *equality) diffEmpty(a, b reflect.Value, path string, diffs *[]string) {
+	if e.nilEqualsEmpty || a.Kind() == reflect.Array || a.IsNil() == b.IsNil() {
+		return
+	}
+
+	*diffs = append(*diffs, fmt.Sprintf("%s: %#v != %#v", pathName(path), a, b))
+}
+
+// pathName returns path or name of compared root value.
+func pathName(path string) string {
+	if path == "" {
+		return "value"
+	}
+
+	return path
+}
@@ -0,0 +1,70 @@
+package automappertest_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/automappertest"
+)
+
+type Event struct {
+	At time.Time
+}
+
+type EventDTO struct {
+	At int64 `mapper:",unixms"`
+}
+
+type Note struct {
+	Body string
+	Size int
+}
+
+type NoteView struct {
+	Body string
+	Size string
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	r := &recorder{}
+	assert.False(t, automappertest.AssertRoundTrip(r, m, User{}, UserDTO{}))
+	assert.Equal(t, []string{"mapping of automappertest_test.User into automappertest_test.UserDTO isn't equivalent:\n" +
+		"Email: 1 != \nAddress.Zip: 1 != "}, r.errors)
+
+	r = &recorder{}
+	assert.True(t, automappertest.AssertRoundTrip(r, m, &User{}, &UserDTO{}, automappertest.WithIgnoredFields("Email", "Address.Zip")))
+	assert.Empty(t, r.errors)
+}
+
+func TestAssertRoundTrip_TimeRounding(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithTimeLocation(time.UTC))
+	event := Event{At: time.Date(2020, 1, 2, 3, 4, 5, 6e6+7, time.UTC)}
+
+	r := &recorder{}
+	assert.False(t, automappertest.AssertRoundTrip(r, m, event, EventDTO{}))
+	assert.True(t, automappertest.AssertRoundTrip(r, m, event, EventDTO{}, automappertest.WithTimeRounding(time.Millisecond)))
+	assert.Len(t, r.errors, 1)
+}
+
+func TestAssertRoundTrip_Forward(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+
+	r := &recorder{}
+	assert.True(t, automappertest.AssertRoundTrip(r, m, Note{Body: "hello"}, NoteView{}))
+	assert.Empty(t, r.errors)
+
+	assert.NoError(t, m.Set(strings.ToUpper))
+	assert.False(t, automappertest.AssertRoundTrip(r, m, Note{Body: "hello"}, NoteView{}))
+	assert.Len(t, r.errors, 1)
+}
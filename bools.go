@@ -0,0 +1,74 @@
+package automapper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var errUnknownBool = errors.New("string is neither true nor false")
+
+// BoolStrings configures conversions between bool and string fields, see WithBoolStrings.
+type BoolStrings struct {
+	// True and False are strings parsed into true and false values, they are matched case-insensitively.
+	// Other strings fail with *ConversionError, unless False is empty, then they are parsed into false.
+	True, False []string
+	// FormatTrue and FormatFalse are strings bools are formatted into.
+	FormatTrue, FormatFalse string
+}
+
+// DefaultBoolStrings parses "true", "1", "yes" and "y" into true, "false", "0", "no" and "n" into false,
+// and formats bools into "true" and "false".
+var DefaultBoolStrings = BoolStrings{
+	True:        []string{"true", "1", "yes", "y"},
+	False:       []string{"false", "0", "no", "n"},
+	FormatTrue:  "true",
+	FormatFalse: "false",
+}
+
+// WithBoolStrings enables conversions between bool and string fields, e.g. of config files or forms,
+// which otherwise require converters, e.g. WithBoolStrings(automapper.DefaultBoolStrings).
+// Conversions of WithWeaklyTypedInput mode use bools strings too.
+func WithBoolStrings(bools BoolStrings) Option {
+	return func(o *options) {
+		o.boolStrings = &bools
+	}
+}
+
+func isBoolString(from, to reflect.Kind) bool {
+	return (from == reflect.String && to == reflect.Bool) || (from == reflect.Bool && to == reflect.String)
+}
+
+// convert parses string from into bool to, or formats bool from into string to.
+func (b *BoolStrings) convert(from, to reflect.Value) error {
+	if from.Kind() == reflect.Bool {
+		if from.Bool() {
+			to.SetString(b.FormatTrue)
+		} else {
+			to.SetString(b.FormatFalse)
+		}
+
+		return nil
+	}
+
+	switch {
+	case containsFold(b.True, from.String()):
+		to.SetBool(true)
+	case len(b.False) == 0 || containsFold(b.False, from.String()):
+		to.SetBool(false)
+	default:
+		return &ConversionError{From: from.Type(), To: to.Type(), Value: from.String(), Err: errUnknownBool}
+	}
+
+	return nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+
+	return false
+}
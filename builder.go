@@ -0,0 +1,168 @@
+package automapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// CreateMap declares mapping from struct type of from into struct type of to, e.g.
+//
+//	err := m.CreateMap(User{}, UserDTO{})
+//
+// Plans of the pair and of nested struct pairs reachable from it are built immediately,
+// so missing converters are reported at startup instead of on first Map call.
+// Converters must be set before the pair is declared.
+func (m *Mapper) CreateMap(from, to interface{}) error {
+	fromType, toType := structType(reflect.TypeOf(from)), structType(reflect.TypeOf(to))
+	if fromType == nil || toType == nil {
+		return fmt.Errorf("%w '%T -> %T'", ErrNotAStruct, from, to)
+	}
+
+	pair := structMappingInfo{from: fromType, to: toType, protobuf: m.opts.protobuf, graphql: m.opts.graphql}
+	m.mu.Lock()
+	m.pairs = append(m.pairs, pair)
+	m.mu.Unlock()
+	return m.buildPlans(pair)
+}
+
+// buildPlans builds plans of pair and nested pairs reachable from it,
+// returning errors of all fields which can't be mapped.
+func (m *Mapper) buildPlans(pair structMappingInfo) error {
+	var errs []error
+	m.walkPlans(pair, func(pair structMappingInfo, plan []fieldOp) {
+		errs = append(errs, planErrors(pair, plan)...)
+	})
+
+	return errors.Join(errs...)
+}
+
+// AssertConfigurationIsValid builds plans of every pair declared with CreateMap and of nested pairs
+// reachable from them, returning errors of all fields which can't be mapped, e.g. because of missing
+// converters or invalid tags, so that gaps of configuration are found once on startup.
+// Checks enable optional diagnostics, e.g. ReportUnusedSources.
+func (m *Mapper) AssertConfigurationIsValid(checks ...ValidationCheck) error {
+	var validation validation
+	for _, check := range checks {
+		check(&validation)
+	}
+
+	m.mu.RLock()
+	pairs := append([]structMappingInfo(nil), m.pairs...)
+	m.mu.RUnlock()
+
+	var errs []error
+	checked := make(map[structMappingInfo]bool)
+	for _, pair := range pairs {
+		if checked[pair] {
+			continue
+		}
+
+		m.walkPlans(pair, func(pair structMappingInfo, plan []fieldOp) {
+			if !checked[pair] {
+				checked[pair] = true
+				errs = append(errs, planErrors(pair, plan)...)
+				if validation.unusedSources {
+					errs = append(errs, unusedSourceErrors(pair, plan)...)
+				}
+			}
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+// planErrors returns errors of fields of plan of pair which can't be mapped.
+func planErrors(pair structMappingInfo, plan []fieldOp) []error {
+	var errs []error
+	for i := range plan {
+		op := &plan[i]
+		// interface destinations are resolved by held values on mapping
+		if op.tagErr != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", pair.to, op.name, op.tagErr))
+		} else if op.require && op.fromType == nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", pair.to, op.name, ErrMissingSource))
+		} else if op.err != nil && op.toType.Kind() != reflect.Interface {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", pair.to, op.name, op.err))
+		}
+	}
+
+	return errs
+}
+
+// walkPlans builds plans of pair and nested pairs reachable from it, passing them to visit
+// breadth first, so that pairs are visited in order of fields reaching them.
+func (m *Mapper) walkPlans(pair structMappingInfo, visit func(pair structMappingInfo, plan []fieldOp)) {
+	visited := map[structMappingInfo]bool{pair: true}
+	queue := []structMappingInfo{pair}
+	st := &mapState{opts: &options{protobuf: pair.protobuf, graphql: pair.graphql}}
+	for len(queue) > 0 {
+		pair, queue = queue[0], queue[1:]
+		plan := m.structPlan(st, pair.from, pair.to)
+		visit(pair, plan)
+		for i := range plan {
+			for _, nested := range opNestedPairs(&plan[i], pair) {
+				if !visited[nested] {
+					visited[nested] = true
+					queue = append(queue, nested)
+				}
+			}
+		}
+	}
+}
+
+// opNestedPairs returns struct pairs op of pair maps, including ones of oneof cases.
+func opNestedPairs(op *fieldOp, pair structMappingInfo) []structMappingInfo {
+	var pairs []structMappingInfo
+	for i := range op.oneofs {
+		pairs = append(pairs, opNestedPairs(&op.oneofs[i].op, pair)...)
+	}
+
+	if op.err != nil || op.tagErr != nil || op.fromType == nil || op.strategy == converterFunc {
+		return pairs
+	}
+
+	return append(pairs, nestedPairs(op.fromType, op.toType, pair)...)
+}
+
+// nestedPairs returns struct pairs found by unwrapping pointers, slices, arrays
+// and maps of from and to in parallel, with modes of pair.
+func nestedPairs(from, to reflect.Type, pair structMappingInfo) []structMappingInfo {
+	for from.Kind() == reflect.Ptr {
+		from = from.Elem()
+	}
+
+	for to.Kind() == reflect.Ptr {
+		to = to.Elem()
+	}
+
+	switch {
+	case from.Kind() == reflect.Struct && to.Kind() == reflect.Struct:
+		if from == to {
+			return nil
+		}
+
+		pair.from, pair.to = from, to
+		return []structMappingInfo{pair}
+	case from.Kind() == reflect.Map && to.Kind() == reflect.Map:
+		return append(nestedPairs(from.Key(), to.Key(), pair), nestedPairs(from.Elem(), to.Elem(), pair)...)
+	case (from.Kind() == reflect.Slice || from.Kind() == reflect.Array) &&
+		(to.Kind() == reflect.Slice || to.Kind() == reflect.Array):
+		return nestedPairs(from.Elem(), to.Elem(), pair)
+	default:
+		return nil
+	}
+}
+
+// structType returns struct type of tp or of pointer to it, nil for other types.
+func structType(tp reflect.Type) reflect.Type {
+	if tp != nil && tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp == nil || tp.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return tp
+}
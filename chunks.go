@@ -0,0 +1,66 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Progress is called by MapChunks after every mapped chunk, mapped elements of total are already
+// in destination slice. Returned error stops mapping and is returned by MapChunks as is.
+type Progress func(mapped, total int) error
+
+// MapChunks maps slice pointed by from into slice pointed by to like Map, but in chunks
+// of chunkSize elements, calling progress after every chunk, e.g. to checkpoint batch jobs
+// or to wait for downstream. Zero chunkSize maps the whole slice as one chunk.
+// Destination slice holds mapped elements only, so it's shorter than source if mapping is stopped.
+func (m *Mapper) MapChunks(from, to interface{}, chunkSize int, progress Progress, opts ...Option) error {
+	valFrom := reflect.ValueOf(from)
+	valTo := reflect.ValueOf(to)
+	err := validateArgs(valFrom, valTo)
+	if err != nil {
+		return m.translateError(err)
+	}
+
+	if valFrom.Kind() != reflect.Ptr || !m.matchers[slices](valFrom.Type().Elem(), valTo.Type().Elem()) {
+		return m.translateError(fmt.Errorf("%w '%s -> %s'", ErrUnsupportedMapping, valFrom.Type(), valTo.Type()))
+	}
+
+	fromSlice, toSlice := valFrom.Elem(), valTo.Elem()
+	total := fromSlice.Len()
+	if chunkSize <= 0 {
+		chunkSize = total
+	}
+
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	if err = st.opts.err(); err != nil {
+		return m.translateError(err)
+	}
+
+	slice := reflect.MakeSlice(toSlice.Type(), total, total)
+	m.initElems(slice)
+	toSlice.Set(slice.Slice(0, 0))
+	for mapped := 0; mapped < total; {
+		end := mapped + chunkSize
+		if end > total {
+			end = total
+		}
+
+		err = m.setArrayRange(st, fromSlice, slice, mapped, end)
+		if err != nil {
+			return m.translateError(err)
+		}
+
+		mapped = end
+		toSlice.Set(slice.Slice(0, mapped))
+		if progress == nil {
+			continue
+		}
+
+		if err = progress(mapped, total); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
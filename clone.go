@@ -0,0 +1,116 @@
+package automapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// defaultCloneMethod is a name of method used to copy fields of identical types, see SetCloneMethod.
+const defaultCloneMethod = "Clone"
+
+// SetCloneMethod sets name of method, which copies fields of identical types instead of assignment,
+// "Clone" by default. The method must have no arguments and return value of receiver type,
+// e.g. func (s Set) Clone() Set, so that types owning internal slices or maps
+// are not shared between source and destination. Empty name disables cloning.
+func (m *Mapper) SetCloneMethod(name string) {
+	m.cloneMethod = name
+	m.resetPlans()
+}
+
+// isCloneMapping reports whether fields of identical types are copied with clone method,
+// or are structs with such fields, which are mapped field by field.
+func (m *Mapper) isCloneMapping(fromType, toType reflect.Type) bool {
+	return fromType == toType && m.cloneMethod != "" && containsCloner(fromType, m.cloneMethod)
+}
+
+func (m *Mapper) mapClonesFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if fromVal.Kind() == reflect.Ptr && fromVal.IsNil() {
+		toVal.Set(fromVal)
+		return nil
+	}
+
+	method, ok := cloneMethod(fromVal.Type(), m.cloneMethod)
+	if !ok {
+		return m.mapStructsFunc(st, fromVal, toVal)
+	}
+
+	if method.Type.In(0) != fromVal.Type() {
+		// method has pointer receiver
+		ptr := reflect.New(fromVal.Type())
+		ptr.Elem().Set(fromVal)
+		fromVal = ptr
+	}
+
+	toVal.Set(method.Func.Call([]reflect.Value{fromVal})[0])
+	return nil
+}
+
+// cloneMethod returns method named name of tp or pointer to it, which returns value of tp.
+func cloneMethod(tp reflect.Type, name string) (reflect.Method, bool) {
+	method, ok := tp.MethodByName(name)
+	if !ok && tp.Kind() != reflect.Ptr && tp.Kind() != reflect.Interface {
+		method, ok = reflect.PointerTo(tp).MethodByName(name)
+	}
+
+	if !ok || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Type.Out(0) != tp {
+		return reflect.Method{}, false
+	}
+
+	return method, true
+}
+
+// clonerKey is a type with a name of clone method.
+type clonerKey struct {
+	tp     reflect.Type
+	method string
+}
+
+// clonersCache holds containsCloner results by clonerKey.
+var clonersCache sync.Map
+
+// containsCloner reports whether tp has clone method, or is a struct (or pointer to struct),
+// which fields have it. Structs with unexported fields are not considered,
+// they are copied as a whole to keep unexported values.
+func containsCloner(tp reflect.Type, method string) bool {
+	key := clonerKey{tp: tp, method: method}
+	if cached, ok := clonersCache.Load(key); ok {
+		if contains, ok := cached.(bool); ok {
+			return contains
+		}
+	}
+
+	contains := checkContainsCloner(tp, method, make(map[reflect.Type]bool))
+	clonersCache.Store(key, contains)
+	return contains
+}
+
+// checkContainsCloner implements containsCloner, visiting guards against recursive types.
+func checkContainsCloner(tp reflect.Type, method string, visiting map[reflect.Type]bool) bool {
+	if _, ok := cloneMethod(tp, method); ok {
+		return true
+	}
+
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct || visiting[tp] {
+		return false
+	}
+
+	visiting[tp] = true
+	fields := cachedFields(tp)
+	for _, meta := range fields.list {
+		if !meta.exported {
+			return false
+		}
+	}
+
+	for _, meta := range fields.list {
+		if checkContainsCloner(tp.Field(meta.index).Type, method, visiting) {
+			return true
+		}
+	}
+
+	return false
+}
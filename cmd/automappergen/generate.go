@@ -0,0 +1,452 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const directive = "//go:automapper "
+
+var (
+	errDirective   = errors.New("invalid directive")
+	errUnsupported = errors.New("unsupported mapping")
+)
+
+type pair struct {
+	from, to *types.Named
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string
+	// exported are pairs requested with directives, other pairs are nested ones.
+	exported map[pair]bool
+	queue    []pair
+	queued   map[pair]bool
+	body     bytes.Buffer
+}
+
+// generate returns source of output file with mapping functions of package in dir.
+func generate(dir, output string) ([]byte, error) {
+	pkg, err := loadPackage(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:      pkg.Types,
+		imports:  make(map[string]string),
+		exported: make(map[pair]bool),
+		queued:   make(map[pair]bool),
+	}
+	pairs, err := g.directives(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pairs {
+		g.exported[p] = true
+		g.enqueue(p)
+	}
+
+	for len(g.queue) > 0 {
+		p := g.queue[0]
+		g.queue = g.queue[1:]
+		err = g.writeFunc(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by automappergen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&src, "import %q\n", path)
+	}
+
+	src.Write(g.body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error in format.Source: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// loadPackage loads package in dir, ignoring previously generated output,
+// which may be stale and not compile.
+func loadPackage(dir, output string) (*packages.Package, error) {
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return nil, fmt.Errorf("error in filepath.Abs: %w", err)
+	}
+
+	overlay := make(map[string][]byte)
+	if _, err = os.Stat(output); err == nil {
+		file, err := parser.ParseFile(token.NewFileSet(), output, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, fmt.Errorf("error in parser.ParseFile: %w", err)
+		}
+
+		overlay[output] = []byte("package " + file.Name.Name + "\n")
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedSyntax | packages.NeedDeps,
+		Dir:     dir,
+		Overlay: overlay,
+	}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error in packages.Load: %w", err)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, got %d", dir, len(pkgs))
+	}
+
+	if len(pkgs[0].Errors) > 0 {
+		return nil, pkgs[0].Errors[0]
+	}
+
+	return pkgs[0], nil
+}
+
+// directives returns pairs of types from //go:automapper From To comments.
+func (g *generator) directives(pkg *packages.Package) ([]pair, error) {
+	var pairs []pair
+	for _, file := range pkg.Syntax {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if !strings.HasPrefix(comment.Text, directive) {
+					continue
+				}
+
+				names := strings.Fields(strings.TrimPrefix(comment.Text, directive))
+				if len(names) != 2 {
+					return nil, fmt.Errorf("%w %q: expected From and To types", errDirective, comment.Text)
+				}
+
+				from, err := g.lookup(names[0])
+				if err != nil {
+					return nil, err
+				}
+
+				to, err := g.lookup(names[1])
+				if err != nil {
+					return nil, err
+				}
+
+				pairs = append(pairs, pair{from: from, to: to})
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+func (g *generator) lookup(name string) (*types.Named, error) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: type %s not found", errDirective, name)
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok || !isStruct(named) {
+		return nil, fmt.Errorf("%w: type %s is not a struct", errDirective, name)
+	}
+
+	return named, nil
+}
+
+func (g *generator) enqueue(p pair) {
+	if !g.queued[p] {
+		g.queued[p] = true
+		g.queue = append(g.queue, p)
+	}
+}
+
+func (g *generator) funcName(p pair) string {
+	prefix := "map"
+	if g.exported[p] {
+		prefix = "Map"
+	}
+
+	return prefix + p.from.Obj().Name() + p.to.Obj().Name()
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+
+	g.imports[pkg.Path()] = pkg.Name()
+	return pkg.Name()
+}
+
+func (g *generator) typeString(tp types.Type) string {
+	return types.TypeString(tp, g.qualifier)
+}
+
+func (g *generator) writeFunc(p pair) error {
+	name := g.funcName(p)
+	if g.exported[p] {
+		fmt.Fprintf(&g.body, "\n// %s maps %s into %s.", name, p.from.Obj().Name(), p.to.Obj().Name())
+	}
+
+	fmt.Fprintf(&g.body, "\nfunc %s(from *%s, to *%s) {\n", name, g.typeString(p.from), g.typeString(p.to))
+	fromStruct, toStruct := structOf(p.from), structOf(p.to)
+	fromFields := make(map[string]*types.Var)
+	fromOpts := make(map[string][]string)
+	for i := 0; i < fromStruct.NumFields(); i++ {
+		field := fromStruct.Field(i)
+		if name, opts := parseTag(field, fromStruct.Tag(i)); field.Exported() && !hasOption(opts, "ignore") {
+			fromFields[name], fromOpts[name] = field, opts
+		}
+	}
+
+	for i := 0; i < toStruct.NumFields(); i++ {
+		toField := toStruct.Field(i)
+		name, opts := parseTag(toField, toStruct.Tag(i))
+		fromField, ok := fromFields[name]
+		if !toField.Exported() || !ok || hasOption(opts, "ignore") {
+			continue
+		}
+
+		err := checkOptions(append(fromOpts[name], opts...))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", p.to.Obj().Name(), toField.Name(), err)
+		}
+
+		err = g.writeField("from."+fromField.Name(), "to."+toField.Name(), fromField.Type(), toField.Type())
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", p.to.Obj().Name(), toField.Name(), err)
+		}
+	}
+
+	g.body.WriteString("}\n")
+	return nil
+}
+
+// writeField writes mapping of from value into to value, skipping zero source values.
+func (g *generator) writeField(from, to string, fromType, toType types.Type) error {
+	switch {
+	case types.Identical(fromType, toType) && hasClone(fromType):
+		g.ifNotZero(from, fromType, func() {
+			fmt.Fprintf(&g.body, "%s = %s.Clone()\n", to, from)
+		})
+		return nil
+	case types.Identical(fromType, toType):
+		g.ifNotZero(from, fromType, func() {
+			fmt.Fprintf(&g.body, "%s = %s\n", to, from)
+		})
+		return nil
+	case isStructOrPtrToStruct(fromType) && isStructOrPtrToStruct(toType):
+		return g.writeStruct(from, to, fromType, toType)
+	}
+
+	fromSlice, okFrom := fromType.Underlying().(*types.Slice)
+	toSlice, okTo := toType.Underlying().(*types.Slice)
+	if okFrom && okTo && isStructOrPtrToStruct(fromSlice.Elem()) && isStructOrPtrToStruct(toSlice.Elem()) {
+		var err error
+		g.ifNotZero(from, fromType, func() {
+			fmt.Fprintf(&g.body, "%s = make(%s, len(%s))\n", to, g.typeString(toType), from)
+			err = g.writeElems(from, to, fromSlice.Elem(), toSlice.Elem())
+		})
+		return err
+	}
+
+	fromArray, okFrom := fromType.Underlying().(*types.Array)
+	toArray, okTo := toType.Underlying().(*types.Array)
+	if okFrom && okTo && isStructOrPtrToStruct(fromArray.Elem()) && isStructOrPtrToStruct(toArray.Elem()) &&
+		fromArray.Len() == toArray.Len() {
+		var err error
+		g.ifNotZero(from, fromType, func() {
+			fmt.Fprintf(&g.body, "%s = %s{}\n", to, g.typeString(toType))
+			err = g.writeElems(from, to, fromArray.Elem(), toArray.Elem())
+		})
+		return err
+	}
+
+	return fmt.Errorf("%w %s -> %s, register converter and use Mapper.Map instead",
+		errUnsupported, g.typeString(fromType), g.typeString(toType))
+}
+
+func (g *generator) writeElems(from, to string, fromElem, toElem types.Type) error {
+	fmt.Fprintf(&g.body, "for i := range %s {\n", from)
+	err := g.writeStruct(from+"[i]", to+"[i]", fromElem, toElem)
+	g.body.WriteString("}\n")
+	return err
+}
+
+// writeStruct writes mapping of struct or pointer to struct values.
+func (g *generator) writeStruct(from, to string, fromType, toType types.Type) error {
+	fromNamed, okFrom := namedStruct(fromType)
+	toNamed, okTo := namedStruct(toType)
+	if !okFrom || !okTo {
+		return fmt.Errorf("%w %s -> %s: unnamed struct types", errUnsupported, g.typeString(fromType), g.typeString(toType))
+	}
+
+	p := pair{from: fromNamed, to: toNamed}
+	g.enqueue(p)
+	src := "&" + from
+	if _, ok := fromType.(*types.Pointer); ok {
+		src = from
+	}
+
+	write := func() {
+		dst := "&" + to
+		if _, ok := toType.(*types.Pointer); ok {
+			fmt.Fprintf(&g.body, "%s = new(%s)\n", to, g.typeString(toNamed))
+			dst = to
+		}
+
+		fmt.Fprintf(&g.body, "%s(%s, %s)\n", g.funcName(p), src, dst)
+	}
+
+	g.ifNotZero(from, fromType, write)
+	return nil
+}
+
+// ifNotZero writes body under zero check of value, values of non-comparable types are never skipped.
+func (g *generator) ifNotZero(value string, tp types.Type, body func()) {
+	var cond string
+	switch under := tp.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case under.Info()&types.IsBoolean != 0:
+			cond = value
+		case under.Info()&types.IsString != 0:
+			cond = value + ` != ""`
+		case under.Info()&types.IsNumeric != 0:
+			cond = value + " != 0"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		cond = value + " != nil"
+	case *types.Struct, *types.Array:
+		if types.Comparable(tp) {
+			cond = fmt.Sprintf("%s != (%s{})", value, g.typeString(tp))
+		}
+	}
+
+	if cond == "" {
+		body()
+		return
+	}
+
+	fmt.Fprintf(&g.body, "if %s {\n", cond)
+	body()
+	g.body.WriteString("}\n")
+}
+
+// hasClone mirrors Mapper clone method: tp has Clone method returning tp.
+func hasClone(tp types.Type) bool {
+	methods := types.NewMethodSet(types.NewPointer(tp))
+	if _, ok := tp.Underlying().(*types.Pointer); ok {
+		methods = types.NewMethodSet(tp)
+	}
+
+	sel := methods.Lookup(nil, "Clone")
+	if sel == nil {
+		return false
+	}
+
+	sig, ok := sel.Type().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), tp)
+}
+
+func structOf(tp types.Type) *types.Struct {
+	if ptr, ok := tp.Underlying().(*types.Pointer); ok {
+		tp = ptr.Elem()
+	}
+
+	st, _ := tp.Underlying().(*types.Struct)
+	return st
+}
+
+func isStruct(tp types.Type) bool {
+	_, ok := tp.Underlying().(*types.Struct)
+	return ok
+}
+
+func isStructOrPtrToStruct(tp types.Type) bool {
+	return structOf(tp) != nil
+}
+
+func namedStruct(tp types.Type) (*types.Named, bool) {
+	if ptr, ok := tp.(*types.Pointer); ok {
+		tp = ptr.Elem()
+	}
+
+	named, ok := tp.(*types.Named)
+	return named, ok && isStruct(named)
+}
+
+// parseTag mirrors automapper tag parsing: `mapper:"name,opt1,opt2=value"`,
+// tag may consist of options only, e.g. `mapper:"csv=3"`. Options are returned without values.
+func parseTag(field *types.Var, tag string) (name string, opts []string) {
+	parts := strings.Split(reflect.StructTag(tag).Get("mapper"), ",")
+	name, values := parts[0], parts[1:]
+	if strings.Contains(name, "=") {
+		name, values = "", parts
+	}
+
+	for _, opt := range values {
+		opt, _, _ = strings.Cut(opt, "=")
+		opts = append(opts, opt)
+	}
+
+	if name == "" {
+		name = field.Name()
+	}
+
+	return name, opts
+}
+
+// generatedOptions are tag options which generated code handles the way Mapper.Map does:
+// ignored fields aren't mapped, and zero source values are never written, as with skipzero.
+// omitempty only affects URL values.
+var generatedOptions = map[string]bool{
+	"ignore":    true,
+	"skipzero":  true,
+	"omitempty": true,
+}
+
+// checkOptions returns error if tag options of mapped fields change mapping in a way generated code doesn't,
+// e.g. unix, require or trim, so that generated functions don't silently differ from Mapper.Map.
+func checkOptions(opts []string) error {
+	for _, opt := range opts {
+		if !generatedOptions[opt] {
+			return fmt.Errorf("%w: tag option %q, use Mapper.Map instead", errUnsupported, opt)
+		}
+	}
+
+	return nil
+}
+
+func hasOption(opts []string, name string) bool {
+	for _, opt := range opts {
+		if opt == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/cmd/automappergen/testdata/models"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join("testdata", "models", "automapper_gen.go")
+	expected, err := os.ReadFile(output)
+	assert.NoError(t, err)
+
+	generated, err := generate(filepath.Join("testdata", "models"), output)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(generated))
+}
+
+func TestGenerate_SameAsMap(t *testing.T) {
+	t.Parallel()
+	from := models.User{
+		ID:        1,
+		Name:      "name",
+		Active:    true,
+		Created:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Address:   models.Address{City: "city"},
+		Home:      &models.Address{City: "home"},
+		Items:     []models.Item{{Name: "item1"}, {Name: "item2"}},
+		Tags:      []string{"tag"},
+		Favorites: [2]*models.Item{{Name: "fav1"}, {Name: "fav2"}},
+		Count:     2,
+		Token:     "token",
+		Updated:   time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	var generated, mapped models.UserDTO
+	models.MapUserUserDTO(&from, &generated)
+	err := automapper.New().Map(&from, &mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, mapped, generated)
+
+	// zero values are skipped
+	updated := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	generated, mapped = models.UserDTO{Name: "keep", Updated: updated}, models.UserDTO{Name: "keep", Updated: updated}
+	models.MapUserUserDTO(&models.User{}, &generated)
+	err = automapper.New().Map(&models.User{}, &mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, mapped, generated)
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join("testdata", "unsupported")
+	_, err := generate(dir, filepath.Join(dir, "automapper_gen.go"))
+	assert.ErrorIs(t, err, errUnsupported)
+}
+
+func TestGenerate_UnsupportedTagOption(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join("testdata", "tagoptions")
+	_, err := generate(dir, filepath.Join(dir, "automapper_gen.go"))
+	assert.ErrorIs(t, err, errUnsupported)
+	assert.EqualError(t, err, `To.ID: unsupported mapping: tag option "require", use Mapper.Map instead`)
+}
@@ -0,0 +1,49 @@
+// Command automappergen generates typed mapping functions for struct types marked
+// with //go:automapper directives, so that generated mapping is refreshed by go generate:
+//
+//	//go:generate automappergen
+//
+//	//go:automapper User UserDTO
+//	type UserDTO struct {
+//		Name string
+//	}
+//
+// For every directive a function MapUserUserDTO(from *User, to *UserDTO) is written
+// to automapper_gen.go next to the types. Fields are matched and mapped the way Mapper.Map
+// does it, generation fails if a field requires a converter or uses a tag option, such as unix,
+// require or trim, which generated code doesn't implement.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory")
+	output := flag.String("output", "automapper_gen.go", "output file name")
+	flag.Parse()
+
+	err := run(*dir, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "automappergen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+	path := filepath.Join(dir, output)
+	src, err := generate(dir, path)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, src, 0o644)
+	if err != nil {
+		return fmt.Errorf("error in WriteFile: %w", err)
+	}
+
+	return nil
+}
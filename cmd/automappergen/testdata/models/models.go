@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+//go:generate go run github.com/lebedevars/automapper/cmd/automappergen
+
+type Address struct {
+	City string
+}
+
+type AddressDTO struct {
+	City string
+}
+
+type Item struct {
+	Name string
+}
+
+type ItemDTO struct {
+	Name string
+}
+
+type User struct {
+	ID        int
+	Name      string
+	Active    bool
+	Created   time.Time
+	Address   Address
+	Home      *Address
+	Items     []Item
+	Tags      []string
+	Favorites [2]*Item
+	Secret    string
+	Count     int `mapper:"total"`
+	Token     string
+	Updated   time.Time
+}
+
+//go:automapper User UserDTO
+type UserDTO struct {
+	ID        int
+	Name      string
+	Active    bool
+	Created   time.Time
+	Address   *AddressDTO
+	Home      AddressDTO
+	Items     []*ItemDTO
+	Tags      []string
+	Favorites [2]ItemDTO
+	secret    string
+	Total     int       `mapper:"total"`
+	Token     string    `mapper:",ignore"`
+	Updated   time.Time `mapper:",skipzero"`
+}
@@ -0,0 +1,12 @@
+package tagoptions
+
+type From struct {
+	ID   string
+	Name string `mapper:",ignore"`
+}
+
+//go:automapper From To
+type To struct {
+	ID   string `mapper:",require"`
+	Name string `mapper:",trim"`
+}
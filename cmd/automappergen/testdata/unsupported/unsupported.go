@@ -0,0 +1,10 @@
+package unsupported
+
+type From struct {
+	ID int
+}
+
+//go:automapper From To
+type To struct {
+	ID string
+}
@@ -0,0 +1,14 @@
+// Command automappervet runs automapper analyzer, standalone or with go vet:
+//
+//	go vet -vettool=$(which automappervet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/lebedevars/automapper/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,234 @@
+package automapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// setString maps string value into to using registered converters,
+// or built-in parsing if enabled. Pointer destinations are allocated.
+func (m *Mapper) setString(st *mapState, value string, to reflect.Value) error {
+	from := reflect.ValueOf(value)
+	mappingType := m.detectMappingType(from.Type(), to.Type())
+	if mappingType != unsupported {
+		return m.strats[mappingType](st, from, to)
+	}
+
+	if st.opts.parseStrings && to.Kind() != reflect.Ptr {
+		parsed, err := parseString(value, to.Type())
+		if err != nil {
+			return err
+		}
+
+		to.Set(parsed)
+		return nil
+	}
+
+	if to.Kind() == reflect.Ptr {
+		elem := reflect.New(to.Type().Elem())
+		err := m.setString(st, value, elem.Elem())
+		if err != nil {
+			return err
+		}
+
+		to.Set(elem)
+		return nil
+	}
+
+	return m.missingConverter(from, to)
+}
+
+// setStrings maps values into slice destination element by element,
+// or the first value into any other destination.
+func (m *Mapper) setStrings(st *mapState, values []string, to reflect.Value) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if to.Type() == reflect.TypeOf(values) {
+		to.Set(reflect.ValueOf(values))
+		return nil
+	}
+
+	if to.Kind() != reflect.Slice {
+		return m.setString(st, values[0], to)
+	}
+
+	slice := reflect.MakeSlice(to.Type(), len(values), len(values))
+	for i, value := range values {
+		err := m.setString(st, value, slice.Index(i))
+		if err != nil {
+			return err
+		}
+	}
+
+	to.Set(slice)
+	return nil
+}
+
+var stringType = reflect.TypeOf("")
+
+// formatString maps from into string using registered converters.
+func (m *Mapper) formatString(st *mapState, from reflect.Value) (string, error) {
+	to := reflect.New(stringType).Elem()
+	mappingType := m.detectMappingType(from.Type(), to.Type())
+	var err error
+	if mappingType == unsupported {
+		err = m.missingConverter(from, to)
+	} else {
+		err = m.strats[mappingType](st, from, to)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return to.String(), nil
+}
+
+// formatStrings maps slice or array elements into strings,
+// nil pointers are skipped, any other value is mapped into one string.
+func (m *Mapper) formatStrings(st *mapState, from reflect.Value) ([]string, error) {
+	if from.Kind() == reflect.Ptr {
+		if from.IsNil() {
+			return nil, nil
+		}
+
+		from = from.Elem()
+	}
+
+	if from.Kind() != reflect.Slice && from.Kind() != reflect.Array {
+		value, err := m.formatString(st, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{value}, nil
+	}
+
+	values := make([]string, 0, from.Len())
+	for i := 0; i < from.Len(); i++ {
+		value, err := m.formatString(st, from.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseString parses value into basic kinds with strconv, and time.Duration with time.ParseDuration.
+func parseString(value string, tp reflect.Type) (reflect.Value, error) {
+	val, err := parseBasic(value, tp)
+	if errors.Is(err, ErrMissingConverter) {
+		return reflect.Value{}, err
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("%w: %v", ErrConverter, err)
+	}
+
+	return val, nil
+}
+
+// parseBasic implements parseString, returning parsing errors as is.
+func parseBasic(value string, tp reflect.Type) (reflect.Value, error) {
+	val := reflect.New(tp).Elem()
+	var err error
+	switch {
+	case tp == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(value)
+		val.SetInt(int64(d))
+	case tp.Kind() == reflect.String:
+		val.SetString(value)
+	case tp.Kind() == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(value)
+		val.SetBool(b)
+	case tp.Kind() >= reflect.Int && tp.Kind() <= reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(value, 10, tp.Bits())
+		val.SetInt(i)
+	case tp.Kind() >= reflect.Uint && tp.Kind() <= reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(value, 10, tp.Bits())
+		val.SetUint(u)
+	case tp.Kind() == reflect.Float32 || tp.Kind() == reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(value, tp.Bits())
+		val.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, stringType, tp)
+	}
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return val, nil
+}
+
+// isStringMap reports whether tp is a map with string keys and string or []string values,
+// e.g. environment variables, url.Values or http.Header.
+func isStringMap(tp reflect.Type) bool {
+	if tp.Kind() != reflect.Map || tp.Key().Kind() != reflect.String {
+		return false
+	}
+
+	elem := tp.Elem()
+	return elem.Kind() == reflect.String || (elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String)
+}
+
+// parsingState returns state of the call with built-in parsing of strings into basic kinds enabled,
+// which string maps and url.Values are mapped with, so that both parse the same inputs.
+func parsingState(st *mapState) *mapState {
+	opts := *st.opts
+	opts.parseStrings = true
+	return &mapState{opts: &opts, visited: st.visited, ctx: st.ctx}
+}
+
+// mapStringMap maps string map into struct, parsing values with registered converters or strconv.
+// Keys match destination fields the same way MapMasked paths do.
+func (m *Mapper) mapStringMap(st *mapState, from, to reflect.Value) error {
+	parseState := parsingState(st)
+	keys := make([]string, 0, from.Len())
+	for _, key := range from.MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	sortByFields(to.Type(), keys)
+	for _, key := range keys {
+		toVal, ok := destFieldByKey(st, to, key)
+		if !ok {
+			continue
+		}
+
+		var err error
+		value := from.MapIndex(reflect.ValueOf(key).Convert(from.Type().Key()))
+		if value.Kind() == reflect.String {
+			err = m.setString(parseState, value.String(), toVal)
+		} else {
+			values := make([]string, value.Len())
+			for i := range values {
+				values[i] = value.Index(i).String()
+			}
+
+			err = m.setStrings(parseState, values, toVal)
+		}
+
+		if err != nil {
+			meta, _ := cachedFields(to.Type()).fieldByKey(key)
+			return withFieldPath(err, meta.name)
+		}
+	}
+
+	return nil
+}
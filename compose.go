@@ -0,0 +1,74 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Compose registers converter from input type of the first converter into output type
+// of the last one, which calls converters one after another, e.g. composition of
+// func(int) string and func(string) (time.Duration, error) maps int into time.Duration.
+// Converters must be in one of the forms accepted by Set, composition stops on the first
+// converter error.
+func (m *Mapper) Compose(converters ...interface{}) error {
+	if len(converters) == 0 {
+		return ErrNotAFn
+	}
+
+	fns := make([]reflect.Value, 0, len(converters))
+	withError := false
+	for _, converter := range converters {
+		fn := reflect.ValueOf(converter)
+		if fn.Kind() != reflect.Func {
+			return ErrNotAFn
+		}
+
+		tp := fn.Type()
+		if tp.IsVariadic() || tp.NumIn() != 1 || tp.NumOut() == 0 || tp.NumOut() > 2 {
+			return fmt.Errorf("%w: %s", ErrComposition, tp)
+		}
+
+		if tp.NumOut() == 2 {
+			if !tp.Out(1).Implements(errorType) {
+				return ErrConverterErrorUnknownType
+			}
+
+			withError = true
+		}
+
+		if len(fns) > 0 && fns[len(fns)-1].Type().Out(0) != tp.In(0) {
+			return fmt.Errorf("%w '%s -> %s'", ErrComposition, fns[len(fns)-1].Type().Out(0), tp.In(0))
+		}
+
+		fns = append(fns, fn)
+	}
+
+	outs := []reflect.Type{fns[len(fns)-1].Type().Out(0)}
+	if withError {
+		outs = append(outs, errorType)
+	}
+
+	fnType := reflect.FuncOf([]reflect.Type{fns[0].Type().In(0)}, outs, false)
+	composed := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		val := args[0]
+		for _, fn := range fns {
+			outArgs := fn.Call([]reflect.Value{val})
+			if len(outArgs) == 2 && !isNilError(outArgs[1]) {
+				// error type of converter may differ from error interface
+				err := reflect.New(errorType).Elem()
+				err.Set(outArgs[1])
+				return []reflect.Value{reflect.Zero(outs[0]), err}
+			}
+
+			val = outArgs[0]
+		}
+
+		if withError {
+			return []reflect.Value{val, reflect.Zero(errorType)}
+		}
+
+		return []reflect.Value{val}
+	})
+
+	return m.Set(composed.Interface())
+}
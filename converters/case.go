@@ -0,0 +1,104 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/lebedevars/automapper"
+)
+
+// Names of case converters registered by RegisterCase, e.g. `mapper:",converter=snake"`.
+const (
+	CaseSnake = "snake"
+	CaseCamel = "camel"
+	CaseKebab = "kebab"
+	CaseTitle = "title"
+)
+
+// RegisterCase registers Snake, Camel, Kebab and Title as named converters, see Mapper.SetNamed,
+// so that fields select them by tag instead of every string being converted.
+func RegisterCase(m *automapper.Mapper) error {
+	for name, converter := range map[string]func(string) string{
+		CaseSnake: Snake,
+		CaseCamel: Camel,
+		CaseKebab: Kebab,
+		CaseTitle: Title,
+	} {
+		err := m.SetNamed(name, converter)
+		if err != nil {
+			return fmt.Errorf("error in SetNamed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Snake converts identifier into snake case, e.g. "UserID" into "user_id".
+func Snake(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// Kebab converts identifier into kebab case, e.g. "UserID" into "user-id".
+func Kebab(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+// Camel converts identifier into lower camel case, e.g. "user_id" into "userId".
+func Camel(s string) string {
+	parts := words(s)
+	for i, word := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(word)
+			continue
+		}
+
+		parts[i] = capitalize(word)
+	}
+
+	return strings.Join(parts, "")
+}
+
+// Title converts identifier into capitalized words, e.g. "user_id" into "User Id".
+func Title(s string) string {
+	parts := words(s)
+	for i, word := range parts {
+		parts[i] = capitalize(word)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// words splits identifier by separators, e.g. "_", "-" and spaces, and by case changes,
+// keeping acronyms together, e.g. "HTTPServer_port" into "HTTP", "Server" and "port".
+func words(s string) []string {
+	var parts []string
+	runes := []rune(s)
+	start := 0
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			if i > start {
+				parts = append(parts, string(runes[start:i]))
+			}
+
+			start = i + 1
+		case i > start && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		parts = append(parts, string(runes[start:]))
+	}
+
+	return parts
+}
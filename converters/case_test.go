@@ -0,0 +1,55 @@
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/converters"
+)
+
+type Column struct {
+	Name    string
+	Label   string
+	Param   string
+	Display string
+}
+
+type ColumnDTO struct {
+	Name    string `mapper:",converter=snake"`
+	Label   string `mapper:",converter=camel"`
+	Param   string `mapper:",converter=kebab"`
+	Display string `mapper:",converter=title"`
+}
+
+func TestRegisterCase(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := converters.RegisterCase(m)
+	assert.NoError(t, err)
+
+	dto := ColumnDTO{}
+	err = m.Map(&Column{Name: "UserID", Label: "created_at", Param: "pageSize", Display: "HTTPServer_port"}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, ColumnDTO{Name: "user_id", Label: "createdAt", Param: "page-size", Display: "Http Server Port"}, dto)
+}
+
+func TestCase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in, snake, camel, kebab, title string
+	}{
+		{"", "", "", "", ""},
+		{"ID", "id", "id", "id", "Id"},
+		{"userName", "user_name", "userName", "user-name", "User Name"},
+		{"XMLHttpRequest", "xml_http_request", "xmlHttpRequest", "xml-http-request", "Xml Http Request"},
+		{"order-item 2", "order_item_2", "orderItem2", "order-item-2", "Order Item 2"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.snake, converters.Snake(tt.in), tt.in)
+		assert.Equal(t, tt.camel, converters.Camel(tt.in), tt.in)
+		assert.Equal(t, tt.kebab, converters.Kebab(tt.in), tt.in)
+		assert.Equal(t, tt.title, converters.Title(tt.in), tt.in)
+	}
+}
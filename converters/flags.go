@@ -0,0 +1,121 @@
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lebedevars/automapper"
+)
+
+var ErrUnknownFlag = errors.New("unknown flag")
+
+// Bitmask is a type of bit flag sets.
+type Bitmask interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// UnknownBits sets mapping of bits and names missing in flag table.
+type UnknownBits int
+
+const (
+	// IgnoreUnknownBits drops unknown bits and names.
+	IgnoreUnknownBits UnknownBits = iota
+	// RejectUnknownBits fails mapping of unknown bits and names with ErrUnknownFlag.
+	RejectUnknownBits
+	// KeepUnknownBits formats unknown bits as hex name, e.g. "0x10", which is parsed back.
+	KeepUnknownBits
+)
+
+// Flag is a named bit of flag table.
+type Flag[T Bitmask] struct {
+	Bit  T
+	Name string
+}
+
+// Flags maps bitmasks into slices of flag names and back, e.g. Permissions(5) into []string{"read", "exec"}.
+type Flags[T Bitmask] struct {
+	flags   []Flag[T]
+	unknown UnknownBits
+}
+
+// NewFlags returns flag table of flags, which names are formatted in order of flags.
+// Bitmask type should be a named type, e.g. Permissions, since converters are set for all its values.
+func NewFlags[T Bitmask](unknown UnknownBits, flags ...Flag[T]) *Flags[T] {
+	return &Flags[T]{flags: flags, unknown: unknown}
+}
+
+// Register sets converters between T and []string values.
+func (f *Flags[T]) Register(m *automapper.Mapper) error {
+	for _, converter := range []interface{}{f.Names, f.Parse} {
+		err := m.Set(converter)
+		if err != nil {
+			return fmt.Errorf("error in Set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Names returns names of flags set in v.
+func (f *Flags[T]) Names(v T) ([]string, error) {
+	var names []string
+	rest := v
+	for _, flag := range f.flags {
+		if v&flag.Bit == flag.Bit && flag.Bit != 0 {
+			names = append(names, flag.Name)
+			rest &^= flag.Bit
+		}
+	}
+
+	if rest == 0 {
+		return names, nil
+	}
+
+	switch f.unknown {
+	case RejectUnknownBits:
+		return nil, fmt.Errorf("%w %#x", ErrUnknownFlag, uint64(rest))
+	case KeepUnknownBits:
+		return append(names, fmt.Sprintf("%#x", uint64(rest))), nil
+	default:
+		return names, nil
+	}
+}
+
+// Parse returns bitmask of flags with names.
+func (f *Flags[T]) Parse(names []string) (T, error) {
+	var v T
+	for _, name := range names {
+		bit, ok := f.bit(name)
+		if ok {
+			v |= bit
+			continue
+		}
+
+		if f.unknown == IgnoreUnknownBits {
+			continue
+		}
+
+		if f.unknown == KeepUnknownBits && strings.HasPrefix(name, "0x") {
+			if bits, err := strconv.ParseUint(name[2:], 16, 64); err == nil {
+				v |= T(bits)
+				continue
+			}
+		}
+
+		return 0, fmt.Errorf("%w %q", ErrUnknownFlag, name)
+	}
+
+	return v, nil
+}
+
+func (f *Flags[T]) bit(name string) (T, bool) {
+	for _, flag := range f.flags {
+		if flag.Name == name {
+			return flag.Bit, true
+		}
+	}
+
+	return 0, false
+}
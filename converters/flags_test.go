@@ -0,0 +1,77 @@
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/converters"
+)
+
+type Permissions uint32
+
+const (
+	PermRead Permissions = 1 << iota
+	PermWrite
+	PermExec
+)
+
+type Role struct {
+	Permissions Permissions
+}
+
+type RoleDTO struct {
+	Permissions []string
+}
+
+func newPermissions(unknown converters.UnknownBits) *converters.Flags[Permissions] {
+	return converters.NewFlags(unknown,
+		converters.Flag[Permissions]{Bit: PermRead, Name: "read"},
+		converters.Flag[Permissions]{Bit: PermWrite, Name: "write"},
+		converters.Flag[Permissions]{Bit: PermExec, Name: "exec"},
+	)
+}
+
+func TestFlags_Register(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := newPermissions(converters.RejectUnknownBits).Register(m)
+	assert.NoError(t, err)
+
+	dto := RoleDTO{}
+	err = m.Map(&Role{Permissions: PermRead | PermExec}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleDTO{Permissions: []string{"read", "exec"}}, dto)
+
+	role := Role{}
+	err = m.Map(&RoleDTO{Permissions: []string{"write", "read"}}, &role)
+	assert.NoError(t, err)
+	assert.Equal(t, Role{Permissions: PermRead | PermWrite}, role)
+
+	err = m.Map(&Role{Permissions: PermRead | 1<<4}, &RoleDTO{})
+	assert.ErrorIs(t, err, converters.ErrUnknownFlag)
+
+	err = m.Map(&RoleDTO{Permissions: []string{"admin"}}, &Role{})
+	assert.ErrorIs(t, err, converters.ErrUnknownFlag)
+}
+
+func TestFlags_UnknownBits(t *testing.T) {
+	t.Parallel()
+	names, err := newPermissions(converters.IgnoreUnknownBits).Names(PermWrite | 1<<4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"write"}, names)
+
+	keep := newPermissions(converters.KeepUnknownBits)
+	names, err = keep.Names(PermWrite | 1<<4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"write", "0x10"}, names)
+
+	v, err := keep.Parse(names)
+	assert.NoError(t, err)
+	assert.Equal(t, PermWrite|1<<4, v)
+
+	v, err = newPermissions(converters.IgnoreUnknownBits).Parse([]string{"exec", "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, PermExec, v)
+}
@@ -0,0 +1,131 @@
+// Package converters contains optional converter packs for Mapper.
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/lebedevars/automapper"
+)
+
+var ErrInvalidNumber = errors.New("invalid localized number")
+
+// Percent is a ratio formatted as localized percentage, e.g. 0.125 is "12.5%" in English.
+type Percent float64
+
+// Locale formats and parses numbers for a language. Numbers must use latin digits.
+type Locale struct {
+	printer           *message.Printer
+	maxFractionDigits int
+	group, decimal    string
+}
+
+// NewLocale returns Locale for tag, fractional values are rounded to maxFractionDigits.
+func NewLocale(tag language.Tag, maxFractionDigits int) *Locale {
+	l := &Locale{
+		printer:           message.NewPrinter(tag),
+		maxFractionDigits: maxFractionDigits,
+	}
+	// separators are taken from formatted sample, e.g. "1,234.5"
+	sample := l.printer.Sprint(number.Decimal(1234.5, number.MaxFractionDigits(1)))
+	l.group = between(sample, "1", "2")
+	l.decimal = between(sample, "4", "5")
+	return l
+}
+
+// Register sets converters between strings and int, float64 and Percent values.
+func (l *Locale) Register(m *automapper.Mapper) error {
+	for _, converter := range []interface{}{l.FormatInt, l.ParseInt, l.FormatFloat, l.ParseFloat, l.FormatPercent, l.ParsePercent} {
+		err := m.Set(converter)
+		if err != nil {
+			return fmt.Errorf("error in Set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FormatInt formats v with group separators, e.g. "1.234" in German.
+func (l *Locale) FormatInt(v int) string {
+	return l.printer.Sprint(number.Decimal(v))
+}
+
+// ParseInt parses integer formatted with FormatInt.
+func (l *Locale) ParseInt(s string) (int, error) {
+	v, err := strconv.Atoi(l.normalize(s))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+	}
+
+	return v, nil
+}
+
+// FormatFloat formats v with group and decimal separators, e.g. "1.234,5" in German.
+func (l *Locale) FormatFloat(v float64) string {
+	return l.printer.Sprint(number.Decimal(v, number.MaxFractionDigits(l.maxFractionDigits)))
+}
+
+// ParseFloat parses number formatted with FormatFloat.
+func (l *Locale) ParseFloat(s string) (float64, error) {
+	v, err := strconv.ParseFloat(l.normalize(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+	}
+
+	return v, nil
+}
+
+// FormatPercent formats v as percentage, e.g. "12,5 %" in German.
+func (l *Locale) FormatPercent(v Percent) string {
+	return l.printer.Sprint(number.Percent(float64(v), number.MaxFractionDigits(l.maxFractionDigits)))
+}
+
+// ParsePercent parses percentage formatted with FormatPercent.
+func (l *Locale) ParsePercent(s string) (Percent, error) {
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), "%\u00a0\u202f"))
+	v, err := l.ParseFloat(trimmed)
+	if err != nil || trimmed == strings.TrimSpace(s) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+	}
+
+	return Percent(v / 100), nil
+}
+
+// normalize returns s without group separators and with "." decimal separator.
+func (l *Locale) normalize(s string) string {
+	s = strings.TrimSpace(s)
+	if l.group != "" {
+		s = strings.ReplaceAll(s, l.group, "")
+		if strings.TrimSpace(l.group) == "" {
+			// no-break spaces are often typed as regular ones
+			s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(s)
+		}
+	}
+
+	if l.decimal != "." {
+		if strings.Contains(s, ".") {
+			// "." isn't a separator in locale, so it's not a number of the locale
+			return ""
+		}
+
+		s = strings.ReplaceAll(s, l.decimal, ".")
+	}
+
+	return s
+}
+
+func between(s, left, right string) string {
+	start := strings.Index(s, left)
+	end := strings.Index(s, right)
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return s[start+len(left) : end]
+}
@@ -0,0 +1,55 @@
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/converters"
+)
+
+type Product struct {
+	Stock    int
+	Price    float64
+	Discount converters.Percent
+}
+
+type ProductDTO struct {
+	Stock    string
+	Price    string
+	Discount string
+}
+
+func TestLocale_Register(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := converters.NewLocale(language.German, 2).Register(m)
+	assert.NoError(t, err)
+
+	dto := ProductDTO{}
+	err = m.Map(&Product{Stock: 1234, Price: 1234.5, Discount: 0.125}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, ProductDTO{Stock: "1.234", Price: "1.234,5", Discount: "12,5 %"}, dto)
+
+	product := Product{}
+	err = m.Map(&dto, &product)
+	assert.NoError(t, err)
+	assert.Equal(t, Product{Stock: 1234, Price: 1234.5, Discount: 0.125}, product)
+}
+
+func TestLocale_Parse(t *testing.T) {
+	t.Parallel()
+	fr := converters.NewLocale(language.French, 2)
+
+	v, err := fr.ParseFloat("1 234,5")
+	assert.NoError(t, err)
+	assert.Equal(t, 1234.5, v)
+
+	_, err = fr.ParseFloat("1,234.5")
+	assert.ErrorIs(t, err, converters.ErrInvalidNumber)
+
+	_, err = fr.ParsePercent("12")
+	assert.ErrorIs(t, err, converters.ErrInvalidNumber)
+}
@@ -0,0 +1,62 @@
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+
+	"github.com/lebedevars/automapper"
+)
+
+var ErrInvalidIP = errors.New("invalid IP address")
+
+// RegisterNet sets converters between strings and net.IP, netip.Addr, netip.Prefix,
+// url.URL and *url.URL values.
+func RegisterNet(m *automapper.Mapper) error {
+	for _, converter := range []interface{}{
+		FormatIP, ParseIP,
+		netip.Addr.String, netip.ParseAddr,
+		netip.Prefix.String, netip.ParsePrefix,
+		FormatURL, ParseURL,
+		(*url.URL).String, url.Parse,
+	} {
+		err := m.Set(converter)
+		if err != nil {
+			return fmt.Errorf("error in Set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FormatIP formats ip, e.g. "192.0.2.1" or "2001:db8::1".
+func FormatIP(ip net.IP) string {
+	return ip.String()
+}
+
+// ParseIP parses IPv4 or IPv6 address.
+func ParseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidIP, s)
+	}
+
+	return ip, nil
+}
+
+// FormatURL formats u, e.g. "https://example.com/path?q=1".
+func FormatURL(u url.URL) string {
+	return u.String()
+}
+
+// ParseURL parses URL, which may be relative.
+func ParseURL(s string) (url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return url.URL{}, fmt.Errorf("error in Parse: %w", err)
+	}
+
+	return *u, nil
+}
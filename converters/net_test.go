@@ -0,0 +1,61 @@
+package converters_test
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+	"github.com/lebedevars/automapper/converters"
+)
+
+type Host struct {
+	IP       net.IP
+	Addr     netip.Addr
+	Subnet   netip.Prefix
+	Endpoint url.URL
+	Proxy    *url.URL
+}
+
+type HostDTO struct {
+	IP       string
+	Addr     string
+	Subnet   string
+	Endpoint string
+	Proxy    string
+}
+
+func TestRegisterNet(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := converters.RegisterNet(m)
+	assert.NoError(t, err)
+
+	dto := HostDTO{
+		IP:       "192.0.2.1",
+		Addr:     "2001:db8::1",
+		Subnet:   "10.0.0.0/8",
+		Endpoint: "https://example.com/api?v=1",
+		Proxy:    "http://proxy:3128",
+	}
+	host := Host{}
+	err = m.Map(&dto, &host)
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("192.0.2.1"), host.IP)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::1"), host.Addr)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), host.Subnet)
+	assert.Equal(t, "example.com", host.Endpoint.Host)
+	assert.Equal(t, "proxy:3128", host.Proxy.Host)
+
+	mapped := HostDTO{}
+	err = m.Map(&host, &mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dto, mapped)
+
+	err = m.Map(&HostDTO{IP: "localhost"}, &Host{})
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.ErrorIs(t, err, converters.ErrInvalidIP)
+}
@@ -0,0 +1,100 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StrategyOrder is a position of custom strategy relative to built-in ones.
+type StrategyOrder int
+
+const (
+	// BeforeBuiltins strategies take precedence over converters and automatic mapping.
+	BeforeBuiltins StrategyOrder = iota
+	// AfterBuiltins strategies are used for types no built-in strategy supports,
+	// instead of returning ErrMissingConverter.
+	AfterBuiltins
+)
+
+// RegisterStrategy registers custom strategy, which maps values with fn when match
+// reports true for their types. Strategies with the same order are tried in registration order.
+// Name identifies strategy in exported plans and must be unique.
+func (m *Mapper) RegisterStrategy(name string, order StrategyOrder, match func(fromType, toType reflect.Type) bool,
+	fn func(from, to reflect.Value) error,
+) error {
+	for _, existing := range m.names {
+		if existing == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateStrategy, name)
+		}
+	}
+
+	strategy := customStrategies + supportedType(len(m.names)-len(strategyNames))
+	m.names[strategy] = name
+	m.matchers[strategy] = match
+	m.strats[strategy] = func(st *mapState, from, to reflect.Value) error {
+		return fn(from, to)
+	}
+
+	if order == BeforeBuiltins {
+		// before built-ins, after custom strategies registered earlier
+		i := 0
+		for i < len(m.order) && m.order[i] >= customStrategies {
+			i++
+		}
+
+		m.order = append(m.order[:i], append([]supportedType{strategy}, m.order[i:]...)...)
+	} else {
+		m.order = append(m.order, strategy)
+	}
+
+	m.resetPlans()
+	return nil
+}
+
+// SetPrecedence moves strategies with names to the beginning of precedence in the given order,
+// other strategies keep their relative order. By default registered converters take precedence
+// over everything else, e.g. SetPrecedence(StrategySameTypes) makes values of identical types
+// assigned even when a converter for them is registered.
+func (m *Mapper) SetPrecedence(names ...string) error {
+	first := make([]supportedType, 0, len(names))
+	for _, name := range names {
+		strategy, ok := m.strategy(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownStrategy, name)
+		}
+
+		first = append(first, strategy)
+	}
+
+	order := first
+	for _, strategy := range m.order {
+		if !containsStrategy(first, strategy) {
+			order = append(order, strategy)
+		}
+	}
+
+	m.order = order
+	m.resetPlans()
+	return nil
+}
+
+// strategy returns strategy by its name.
+func (m *Mapper) strategy(name string) (supportedType, bool) {
+	for strategy, existing := range m.names {
+		if existing == name && strategy != unsupported {
+			return strategy, true
+		}
+	}
+
+	return unsupported, false
+}
+
+func containsStrategy(strategies []supportedType, strategy supportedType) bool {
+	for _, s := range strategies {
+		if s == strategy {
+			return true
+		}
+	}
+
+	return false
+}
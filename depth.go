@@ -0,0 +1,35 @@
+package automapper
+
+import "fmt"
+
+// DepthError is returned when structs are nested deeper than WithMaxDepth allows.
+type DepthError struct {
+	// Path is a path of destination field mapping was stopped at, e.g. "Next.Next".
+	Path     string
+	MaxDepth int
+}
+
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("%s: field %s: max depth %d", ErrMaxDepth, e.Path, e.MaxDepth)
+}
+
+func (e *DepthError) Unwrap() error {
+	return ErrMaxDepth
+}
+
+func (e *DepthError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *DepthError) fieldPath() string {
+	return e.Path
+}
+
+// WithMaxDepth limits nesting of mapped structs, the root struct has depth 1.
+// Mapping of structs nested deeper fails with *DepthError, so that inputs from untrusted
+// clients can't make mapping consume unbounded stack. Zero depth means no limit.
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
@@ -0,0 +1,121 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Description describes mapping between two struct types and nested struct types reachable from them.
+type Description struct {
+	// Mappings are mappings of the pair and its nested pairs in order of fields reaching them.
+	// Fields include destination fields without sources, which have empty Source.
+	Mappings []MappingInfo
+}
+
+// Describe builds plans of mapping from struct type of from into struct type of to and of nested
+// struct pairs like CreateMap does, describing every destination field, its source, strategy
+// and converter, e.g. for architecture reviews:
+//
+//	description, err := m.Describe(User{}, UserDTO{})
+//	fmt.Print(description.Markdown())
+func (m *Mapper) Describe(from, to interface{}) (*Description, error) {
+	fromType, toType := structType(reflect.TypeOf(from)), structType(reflect.TypeOf(to))
+	if fromType == nil || toType == nil {
+		return nil, fmt.Errorf("%w '%T -> %T'", ErrNotAStruct, from, to)
+	}
+
+	description := &Description{}
+	pair := structMappingInfo{from: fromType, to: toType, protobuf: m.opts.protobuf, graphql: m.opts.graphql}
+	m.walkPlans(pair, func(pair structMappingInfo, plan []fieldOp) {
+		description.Mappings = append(description.Mappings, m.describePair(pair, plan))
+	})
+
+	return description, nil
+}
+
+// describePair describes plan of pair, adding destination fields plan doesn't map in declaration order.
+func (m *Mapper) describePair(pair structMappingInfo, plan []fieldOp) MappingInfo {
+	mapping := MappingInfo{From: pair.from, To: pair.to, Protobuf: pair.protobuf, GraphQL: pair.graphql}
+	mapped := make(map[int]bool, len(plan))
+	for i := range plan {
+		op := &plan[i]
+		index := op.toIndex
+		if len(op.toPath) > 0 {
+			index = op.toPath[0]
+		}
+
+		mapped[index] = true
+		mapping.Fields = append(mapping.Fields, m.fieldPlan(pair, op))
+	}
+
+	for i := 0; i < pair.to.NumField(); i++ {
+		field := pair.to.Field(i)
+		if field.IsExported() && !mapped[i] {
+			mapping.Fields = append(mapping.Fields, FieldPlan{Name: field.Name, To: field.Type})
+		}
+	}
+
+	return mapping
+}
+
+// String formats description as text, a line per destination field, e.g. "  Name <- FullName (sameTypes)".
+func (d *Description) String() string {
+	var text strings.Builder
+	for i, mapping := range d.Mappings {
+		if i > 0 {
+			text.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&text, "%s -> %s\n", mapping.From, mapping.To)
+		for _, field := range mapping.Fields {
+			fmt.Fprintf(&text, "  %s <- %s\n", field.Name, describeSource(field))
+		}
+	}
+
+	return text.String()
+}
+
+// Markdown formats description as a section with a table per mapping.
+func (d *Description) Markdown() string {
+	var text strings.Builder
+	for i, mapping := range d.Mappings {
+		if i > 0 {
+			text.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&text, "## `%s` → `%s`\n\n", mapping.From, mapping.To)
+		text.WriteString("| Field | Type | Source | Strategy | Converter | Error |\n")
+		text.WriteString("|---|---|---|---|---|---|\n")
+		for _, field := range mapping.Fields {
+			var errText string
+			if field.Err != nil {
+				errText = field.Err.Error()
+			}
+
+			fmt.Fprintf(&text, "| %s | `%s` | %s | %s | %s | %s |\n",
+				field.Name, field.To, markdownCell(field.Source), field.Strategy, markdownCell(field.Converter), markdownCell(errText))
+		}
+	}
+
+	return text.String()
+}
+
+// describeSource describes source, strategy and converter or error of field.
+func describeSource(field FieldPlan) string {
+	switch {
+	case field.Source == "" && field.Strategy == "":
+		return "unmapped"
+	case field.Err != nil:
+		return fmt.Sprintf("%s (%v)", field.Source, field.Err)
+	case field.Converter != "":
+		return fmt.Sprintf("%s (%s: %s)", field.Source, field.Strategy, field.Converter)
+	default:
+		return fmt.Sprintf("%s (%s)", field.Source, field.Strategy)
+	}
+}
+
+// markdownCell escapes pipes of table cell text.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
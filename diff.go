@@ -0,0 +1,158 @@
+package automapper
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Diff returns paths of destination fields which values would be changed by Map(from, to).
+// Destination is not modified. Paths consist of field names (or mapper tags) separated by dots,
+// slice and array elements are addressed by index, e.g. "Items[0].Name".
+// Fields are reported in declaration order.
+func (m *Mapper) Diff(from, to interface{}) ([]string, error) {
+	valTo := reflect.ValueOf(to)
+	if valTo.Kind() != reflect.Ptr || valTo.IsNil() {
+		return nil, m.translateError(ErrNotAPtr)
+	}
+
+	// mapping writes through pointers held by destination, e.g. into structs held by
+	// interface fields, so destination is copied deeply to keep to intact
+	mapped := reflect.New(valTo.Elem().Type())
+	mapped.Elem().Set(deepCopy(valTo.Elem(), make(map[uintptr]reflect.Value)))
+	err := m.Map(from, mapped.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return diffValues("", valTo.Elem(), mapped.Elem(), nil), nil
+}
+
+// deepCopy returns copy of val, which shares no pointers, slices or maps with it.
+// Unexported fields are copied shallowly, copies holds copies of visited pointers,
+// so that shared and cyclic pointers are copied once.
+func deepCopy(val reflect.Value, copies map[uintptr]reflect.Value) reflect.Value {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return val
+		}
+
+		if cp, ok := copies[val.Pointer()]; ok && cp.Type() == val.Type() {
+			return cp
+		}
+
+		cp := reflect.New(val.Type().Elem())
+		copies[val.Pointer()] = cp
+		cp.Elem().Set(deepCopy(val.Elem(), copies))
+		return cp
+	case reflect.Interface:
+		if val.IsNil() {
+			return val
+		}
+
+		cp := reflect.New(val.Type()).Elem()
+		cp.Set(deepCopy(val.Elem(), copies))
+		return cp
+	case reflect.Slice:
+		if val.IsNil() {
+			return val
+		}
+
+		cp := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			cp.Index(i).Set(deepCopy(val.Index(i), copies))
+		}
+
+		return cp
+	case reflect.Array:
+		cp := reflect.New(val.Type()).Elem()
+		for i := 0; i < val.Len(); i++ {
+			cp.Index(i).Set(deepCopy(val.Index(i), copies))
+		}
+
+		return cp
+	case reflect.Map:
+		if val.IsNil() {
+			return val
+		}
+
+		cp := reflect.MakeMapWithSize(val.Type(), val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value(), copies))
+		}
+
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(val.Type()).Elem()
+		cp.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopy(val.Field(i), copies))
+			}
+		}
+
+		return cp
+	default:
+		return val
+	}
+}
+
+// diffValues appends paths of differing values to diff.
+func diffValues(path string, old, mapped reflect.Value, diff []string) []string {
+	if reflect.DeepEqual(old.Interface(), mapped.Interface()) {
+		return diff
+	}
+
+	switch old.Kind() {
+	case reflect.Ptr:
+		if old.IsNil() || mapped.IsNil() {
+			return append(diff, path)
+		}
+
+		return diffValues(path, old.Elem(), mapped.Elem(), diff)
+	case reflect.Slice, reflect.Array:
+		if old.Len() != mapped.Len() {
+			return append(diff, path)
+		}
+
+		for i := 0; i < old.Len(); i++ {
+			diff = diffValues(path+"["+strconv.Itoa(i)+"]", old.Index(i), mapped.Index(i), diff)
+		}
+
+		return diff
+	case reflect.Struct:
+		if !hasSettableFields(old) {
+			return append(diff, path)
+		}
+
+		for _, meta := range cachedFields(old.Type()).list {
+			if !old.Field(meta.index).CanSet() {
+				continue
+			}
+
+			name := meta.name
+			if path != "" {
+				name = path + "." + name
+			}
+
+			diff = diffValues(name, old.Field(meta.index), mapped.Field(meta.index), diff)
+		}
+
+		return diff
+	default:
+		return append(diff, path)
+	}
+}
+
+// hasSettableFields reports whether struct has fields the mapper can write.
+// Structs like time.Time have none and are compared as a whole.
+func hasSettableFields(val reflect.Value) bool {
+	for i := 0; i < val.NumField(); i++ {
+		if val.Field(i).CanSet() {
+			return true
+		}
+	}
+
+	return false
+}
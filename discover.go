@@ -0,0 +1,49 @@
+package automapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DefaultPairSuffixes are suffixes of names of types DiscoverPairs pairs with types without them.
+var DefaultPairSuffixes = []string{"DTO", "Response"}
+
+// DiscoverPairs declares pairs of struct types, or pointers to them, by naming convention instead of
+// calling CreateMap for each: type named with one of suffixes, e.g. UserDTO, is paired with type named
+// without it, e.g. User, in both directions. Types may belong to different packages, nil suffixes
+// stand for DefaultPairSuffixes. Pairs are declared with CreateMap, so errors of fields of all pairs
+// which can't be mapped are returned, types without pairs are ignored.
+func (m *Mapper) DiscoverPairs(suffixes []string, types ...interface{}) error {
+	if suffixes == nil {
+		suffixes = DefaultPairSuffixes
+	}
+
+	byName := make(map[string][]reflect.Type, len(types))
+	structs := make([]reflect.Type, 0, len(types))
+	for _, value := range types {
+		tp := structType(reflect.TypeOf(value))
+		if tp == nil {
+			return fmt.Errorf("%w '%T'", ErrNotAStruct, value)
+		}
+
+		byName[tp.Name()] = append(byName[tp.Name()], tp)
+		structs = append(structs, tp)
+	}
+
+	var errs []error
+	for _, tp := range structs {
+		for _, suffix := range suffixes {
+			if suffix == "" {
+				continue
+			}
+
+			for _, paired := range byName[tp.Name()+suffix] {
+				from, to := reflect.Zero(tp).Interface(), reflect.Zero(paired).Interface()
+				errs = append(errs, m.CreateMap(from, to), m.CreateMap(to, from))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// MapEach maps elements of slice or array from one by one into a reused destination value
+// and passes it to fn, so that large sources can be processed without allocating destination slice.
+// fn must be func(T) error or func(*T) error, pointer destination is reused by the next element,
+// so it must not be retained. Error of fn stops mapping and is returned as is.
+func (m *Mapper) MapEach(from, fn interface{}, opts ...Option) error {
+	fnVal := reflect.ValueOf(fn)
+	if fnVal.Kind() != reflect.Func || fnVal.Type().NumIn() != 1 || fnVal.Type().NumOut() != 1 ||
+		fnVal.Type().Out(0) != errorType {
+		return m.translateError(fmt.Errorf("%w '%T'", ErrNotAFn, fn))
+	}
+
+	fromVal := reflect.ValueOf(from)
+	if fromVal.Kind() == reflect.Ptr && !fromVal.IsNil() {
+		fromVal = fromVal.Elem()
+	}
+
+	if fromVal.Kind() != reflect.Slice && fromVal.Kind() != reflect.Array {
+		return m.translateError(fmt.Errorf("%w '%s -> %s'", ErrUnsupportedMapping, reflect.TypeOf(from), fnVal.Type().In(0)))
+	}
+
+	argType := fnVal.Type().In(0)
+	dstType := argType
+	if argType.Kind() == reflect.Ptr {
+		dstType = argType.Elem()
+	}
+
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	if err := st.opts.err(); err != nil {
+		return m.translateError(err)
+	}
+
+	dst := reflect.New(dstType)
+	zero := reflect.Zero(dstType)
+	arg := dst.Elem()
+	if argType.Kind() == reflect.Ptr {
+		arg = dst
+	}
+
+	for i := 0; i < fromVal.Len(); i++ {
+		dst.Elem().Set(zero)
+		if !fromVal.Index(i).IsZero() {
+			err := m.mapValue(st, fromVal.Index(i), dst.Elem())
+			if err != nil {
+				return m.translateError(withFieldPath(err, "["+strconv.Itoa(i)+"]"))
+			}
+		}
+
+		out := fnVal.Call([]reflect.Value{arg})
+		if !out[0].IsNil() {
+			err, _ := out[0].Interface().(error)
+			return err
+		}
+
+		// destinations of elements are independent, pointers shared by elements are mapped
+		// into distinct values and mapped elements aren't retained until the call returns
+		clear(st.visited)
+	}
+
+	return nil
+}
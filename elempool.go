@@ -0,0 +1,179 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Pool is a source of reusable pointer destinations, e.g. *sync.Pool.
+type Pool interface {
+	Get() interface{}
+	Put(x interface{})
+}
+
+// SetPool sets pool of pointers of type of ptr, e.g. (*Item)(nil), which destinations of the type
+// are taken from whenever mapper allocates them, while it isn't empty: elements of slices of pointers,
+// nested pointers and map values. Values taken from pool are reset to zero before they are mapped into,
+// values of other types returned by pool are dropped. Pointers of pooled types aren't shared with sources,
+// values they point to are copied into pooled pointers, so that mapped destinations can be passed to Release
+// once they aren't used anymore, e.g. after serialization, to return pointers into pools.
+func (m *Mapper) SetPool(ptr interface{}, pool Pool) error {
+	tp := reflect.TypeOf(ptr)
+	if tp == nil || tp.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w '%v'", ErrNotAPtr, tp)
+	}
+
+	if m.pools == nil {
+		m.pools = make(map[reflect.Type]Pool)
+	}
+
+	m.pools[tp.Elem()] = pool
+	m.pooledTypes = &sync.Map{}
+	return nil
+}
+
+// pooled returns reset pointer to value of tp taken from its pool, or new value like newValue
+// if pool is empty, when tp is pooled.
+func (m *Mapper) pooled(tp reflect.Type) (reflect.Value, bool) {
+	pool, ok := m.pools[tp]
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	ptr := reflect.ValueOf(pool.Get())
+	if ptr.IsValid() && ptr.Type() == reflect.PointerTo(tp) && !ptr.IsNil() {
+		ptr.Elem().Set(reflect.Zero(tp))
+		return ptr, true
+	}
+
+	return m.newValue(tp), true
+}
+
+// holdsPooled reports whether values of tp hold pointers of pooled types, which are mapped
+// value by value instead of being copied, so that they aren't shared with sources.
+func (m *Mapper) holdsPooled(tp reflect.Type) bool {
+	if len(m.pools) == 0 {
+		return false
+	}
+
+	if cached, ok := m.pooledTypes.Load(tp); ok {
+		if holds, ok := cached.(bool); ok {
+			return holds
+		}
+	}
+
+	holds := m.checkHoldsPooled(tp, make(map[reflect.Type]bool))
+	m.pooledTypes.Store(tp, holds)
+	return holds
+}
+
+func (m *Mapper) checkHoldsPooled(tp reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[tp] {
+		return false
+	}
+
+	visiting[tp] = true
+	switch tp.Kind() {
+	case reflect.Ptr:
+		_, ok := m.pools[tp.Elem()]
+		return ok || m.checkHoldsPooled(tp.Elem(), visiting)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return m.checkHoldsPooled(tp.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < tp.NumField(); i++ {
+			if tp.Field(i).IsExported() && m.checkHoldsPooled(tp.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mapPooled maps values of identical types holding pooled pointers value by value, allocating pooled pointers.
+func (m *Mapper) mapPooled(st *mapState, fromVal, toVal reflect.Value) error {
+	switch fromVal.Kind() {
+	case reflect.Ptr:
+		if fromVal.IsNil() {
+			toVal.Set(fromVal)
+			return nil
+		}
+
+		if fromVal.Elem().Kind() == reflect.Struct {
+			return m.mapStructsFunc(st, fromVal, toVal)
+		}
+
+		return m.mapPointersFunc(st, fromVal, toVal)
+	case reflect.Slice:
+		if fromVal.IsNil() {
+			toVal.Set(fromVal)
+			return nil
+		}
+
+		return m.mapSlicesFunc(st, fromVal, toVal)
+	case reflect.Array:
+		return m.mapArraysFunc(st, fromVal, toVal)
+	case reflect.Map:
+		return m.mapMapsFunc(st, fromVal, toVal)
+	default:
+		return m.mapStructsFunc(st, fromVal, toVal)
+	}
+}
+
+// Release puts pointers of pooled types held by v, e.g. a mapped slice of pointers, back into their pools,
+// see SetPool. v must be a mapped destination, which pooled pointers aren't shared with anything else.
+// Released pointers held by slices, arrays, maps and fields of addressable structs are set to nil,
+// v must be a pointer for its fields to be cleared. Values held by released pointers are released first.
+// Values held by interfaces are left intact, they may be shared with sources.
+func (m *Mapper) Release(v interface{}) {
+	if len(m.pools) == 0 {
+		return
+	}
+
+	m.release(reflect.ValueOf(v), make(map[uintptr]bool))
+}
+
+// release implements Release, reporting whether val is a released pointer.
+// Released guards against cyclic and shared pointers.
+func (m *Mapper) release(val reflect.Value, released map[uintptr]bool) bool {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() || released[val.Pointer()] {
+			return false
+		}
+
+		released[val.Pointer()] = true
+		m.release(val.Elem(), released)
+		pool, ok := m.pools[val.Type().Elem()]
+		if !ok {
+			return false
+		}
+
+		pool.Put(val.Interface())
+		if val.CanSet() {
+			val.Set(reflect.Zero(val.Type()))
+		}
+
+		return true
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			m.release(val.Index(i), released)
+		}
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			if m.release(iter.Value(), released) {
+				val.SetMapIndex(iter.Key(), reflect.Zero(iter.Value().Type()))
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if val.Type().Field(i).IsExported() {
+				m.release(val.Field(i), released)
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+package automapper
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorCode is a stable machine-readable class of mapping failure.
+type ErrorCode string
+
+const (
+	// CodeMissingConverter means field types can't be mapped without converter.
+	CodeMissingConverter ErrorCode = "missing_converter"
+	// CodeOverflow means numeric value doesn't fit destination type.
+	CodeOverflow ErrorCode = "overflow"
+	// CodeConverterFailed means converter, fallback, strategy or parsing returned error.
+	CodeConverterFailed ErrorCode = "converter_failed"
+	// CodeUnsupportedType means Map arguments can't be mapped, e.g. aren't pointers.
+	CodeUnsupportedType ErrorCode = "unsupported_type"
+	// CodeMaxDepth means structs are nested deeper than WithMaxDepth allows.
+	CodeMaxDepth ErrorCode = "max_depth"
+	// CodeCanceled means context of MapCtx is done.
+	CodeCanceled ErrorCode = "canceled"
+	// CodeNilSource means source is nil, see NilSourceError.
+	CodeNilSource ErrorCode = "nil_source"
+	// CodeSpecialFloat means NaN or ±Inf is mapped into float field, see SpecialFloatError.
+	CodeSpecialFloat ErrorCode = "special_float"
+	// CodeMissingSource means source of required field is missing or zero, see MissingSourceError.
+	CodeMissingSource ErrorCode = "missing_source"
+	// CodeUnusedSource means source field isn't mapped into any destination field, see WithStrictSource.
+	CodeUnusedSource ErrorCode = "unused_source"
+	// CodeInvalidConfig means mapper is misconfigured, e.g. with invalid tag, converter signature or option,
+	// or is called with invalid function, e.g. by MapEach.
+	CodeInvalidConfig ErrorCode = "invalid_config"
+)
+
+// Error is returned by Map, so that failures can be handled by their code:
+//
+//	var mapErr *automapper.Error
+//	if errors.As(err, &mapErr) && mapErr.Code == automapper.CodeConverterFailed {
+//		// respond with 400 Bad Request
+//	}
+//
+// Err is the underlying error, e.g. *NumericError or wrapped ErrMissingConverter.
+type Error struct {
+	Code ErrorCode
+	// Path is a path of destination field, see ErrorTranslator.
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError classifies err of Map.
+func newError(err error) *Error {
+	mapErr := &Error{Code: errorCode(err), Err: err}
+	var pathErr pathError
+	if errors.As(err, &pathErr) {
+		mapErr.Path = pathErr.fieldPath()
+	}
+
+	return mapErr
+}
+
+func errorCode(err error) ErrorCode {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeCanceled
+	case errors.Is(err, ErrMaxDepth):
+		return CodeMaxDepth
+	case errors.Is(err, ErrLossyConversion):
+		return CodeOverflow
+	case errors.Is(err, ErrMissingConverter):
+		return CodeMissingConverter
+	case errors.Is(err, ErrNilSource):
+		return CodeNilSource
+	case errors.Is(err, ErrSpecialFloat):
+		return CodeSpecialFloat
+	case errors.Is(err, ErrMissingSource):
+		return CodeMissingSource
+	case errors.Is(err, ErrUnusedSource):
+		return CodeUnusedSource
+	case errors.Is(err, ErrInvalidTag), errors.Is(err, ErrBadConverterSignature), errors.Is(err, ErrInvalidOption),
+		errors.Is(err, ErrNotAFn):
+		return CodeInvalidConfig
+	case errors.Is(err, ErrNotAPtr), errors.Is(err, ErrNilValue), errors.Is(err, ErrNotAStruct),
+		errors.Is(err, ErrUnsupportedMapping), errors.Is(err, ErrUnsupportedPatch), errors.Is(err, ErrMissingChain):
+		return CodeUnsupportedType
+	default:
+		// errors of user code, e.g. custom strategies, are reported as converter errors
+		return CodeConverterFailed
+	}
+}
@@ -0,0 +1,43 @@
+package automapper
+
+import "reflect"
+
+// SetFactory sets factory of type T, which must be func() *T, used instead of zero values
+// whenever mapper allocates T: nested pointers, elements of slices, arrays and maps,
+// so that types needing initialized maps or defaults are mapped into initialized values.
+// Values returned by factory are mapped into as destinations, so their zero fields keep defaults.
+func (m *Mapper) SetFactory(factory interface{}) error {
+	fn := reflect.TypeOf(factory)
+	if fn == nil || fn.Kind() != reflect.Func || fn.NumIn() != 0 || fn.NumOut() != 1 || fn.Out(0).Kind() != reflect.Ptr {
+		return ErrNotAFactory
+	}
+
+	if m.factories == nil {
+		m.factories = make(map[reflect.Type]reflect.Value)
+	}
+
+	m.factories[fn.Out(0).Elem()] = reflect.ValueOf(factory)
+	return nil
+}
+
+// newValue returns pointer to new value of tp created by registered factory, or to zero value.
+func (m *Mapper) newValue(tp reflect.Type) reflect.Value {
+	if factory, ok := m.factories[tp]; ok {
+		if ptr := factory.Call(nil)[0]; !ptr.IsNil() {
+			return ptr
+		}
+	}
+
+	return reflect.New(tp)
+}
+
+// initElems sets elements of array or slice to values created by registered factory of their type.
+func (m *Mapper) initElems(array reflect.Value) {
+	if _, ok := m.factories[array.Type().Elem()]; !ok {
+		return
+	}
+
+	for i := 0; i < array.Len(); i++ {
+		array.Index(i).Set(m.newValue(array.Type().Elem()).Elem())
+	}
+}
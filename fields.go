@@ -0,0 +1,170 @@
+package automapper
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldMeta is cached metadata of a struct field.
+type fieldMeta struct {
+	index int
+	// name is used to match source and destination fields.
+	name string
+	// key is used to match external keys, e.g. map keys or mask paths, see normalizeFieldName.
+	key      string
+	opts     tagOptions
+	exported bool
+	// err is an error of invalid mapper tag of exported field, see parseTagOptions.
+	err error
+}
+
+// structFields is cached metadata of struct fields in declaration order.
+type structFields struct {
+	list   []fieldMeta
+	byName map[string]int
+	byKey  map[string]int
+	// fieldwise is set if any field is tagged with options checking or transforming its values,
+	// so that struct values are mapped field by field instead of being copied as a whole.
+	fieldwise bool
+}
+
+// fieldsCache holds *structFields by fieldsKey.
+var fieldsCache sync.Map
+
+// fieldsKey is a struct type with name of tag used for fields without mapper tag names.
+type fieldsKey struct {
+	tp          reflect.Type
+	fallbackTag string
+}
+
+// cachedFields returns metadata of struct type fields, building it once per type.
+func cachedFields(tp reflect.Type) *structFields {
+	return cachedTaggedFields(tp, "")
+}
+
+// cachedTaggedFields returns metadata of struct type fields, which names are taken
+// from fallbackTag, e.g. `graphql:"name"`, if fields have no mapper tag names.
+func cachedTaggedFields(tp reflect.Type, fallbackTag string) *structFields {
+	key := fieldsKey{tp: tp, fallbackTag: fallbackTag}
+	if cached, ok := fieldsCache.Load(key); ok {
+		if fields, ok := cached.(*structFields); ok {
+			return fields
+		}
+	}
+
+	fields := &structFields{
+		list:   make([]fieldMeta, 0, tp.NumField()),
+		byName: make(map[string]int, tp.NumField()),
+		byKey:  make(map[string]int, tp.NumField()),
+	}
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		name, opts, err := parseTag(field, fallbackTag)
+		if !field.IsExported() {
+			err = nil
+		}
+
+		fields.fieldwise = fields.fieldwise || opts.fieldwise()
+		fields.byName[name] = len(fields.list)
+		fields.byKey[normalizeFieldName(name)] = len(fields.list)
+		fields.list = append(fields.list, fieldMeta{
+			index:    i,
+			name:     name,
+			key:      normalizeFieldName(name),
+			opts:     opts,
+			exported: field.IsExported(),
+			err:      err,
+		})
+	}
+
+	fieldsCache.Store(key, fields)
+	return fields
+}
+
+// invalidTag returns error of the first field with invalid tag, see parseTagOptions.
+func (f *structFields) invalidTag() error {
+	for i := range f.list {
+		if f.list[i].err != nil {
+			return f.list[i].err
+		}
+	}
+
+	return nil
+}
+
+// field returns metadata of field with name.
+func (f *structFields) field(name string) (*fieldMeta, bool) {
+	i, ok := f.byName[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &f.list[i], true
+}
+
+// publicField returns metadata of exported field with name.
+func (f *structFields) publicField(name string) (*fieldMeta, bool) {
+	meta, ok := f.field(name)
+	return meta, ok && meta.exported
+}
+
+// fieldByKey returns metadata of field matching external key, see normalizeFieldName.
+func (f *structFields) fieldByKey(key string) (*fieldMeta, bool) {
+	i, ok := f.byKey[normalizeFieldName(key)]
+	if !ok {
+		return nil, false
+	}
+
+	return &f.list[i], true
+}
+
+// destFieldByKey returns settable and selected field of struct matching external key.
+func destFieldByKey(st *mapState, to reflect.Value, key string) (reflect.Value, bool) {
+	meta, ok := cachedFields(to.Type()).fieldByKey(key)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	val := to.Field(meta.index)
+	if _, ok = st.field(meta.name); !ok || !val.CanSet() {
+		return reflect.Value{}, false
+	}
+
+	return val, true
+}
+
+// parseTag returns field name and options from `mapper:"name,opt1,opt2=value"` tag,
+// see parseTagOptions. Name from fallbackTag or field name is used if tag has no name.
+func parseTag(field reflect.StructField, fallbackTag string) (name string, opts tagOptions, err error) {
+	mapperTag := field.Tag.Get("mapper")
+	parts := strings.Split(mapperTag, ",")
+	name, options := parts[0], parts[1:]
+	// tag may consist of options only, e.g. `mapper:"csv=3"`
+	if strings.Contains(name, "=") {
+		name, options = "", parts
+	}
+
+	opts, err = parseTagOptions(field.Name, options)
+
+	if name == "" && fallbackTag != "" {
+		if fallback := strings.Split(field.Tag.Get(fallbackTag), ",")[0]; fallback != "-" {
+			name = fallback
+		}
+	}
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, opts, err
+}
+
+func (f *fieldMeta) hasOption(option string) bool {
+	return f.opts.has(option)
+}
+
+// optionValue returns value of `mapper:",option=value"` tag option.
+func (f *fieldMeta) optionValue(option string) (string, bool) {
+	return f.opts.value(option)
+}
@@ -0,0 +1,123 @@
+package automapper
+
+import "reflect"
+
+// Flatten makes embedded structs of types of embedded transparent, e.g. gorm.Model:
+//
+//	m.Flatten(gorm.Model{}, BaseEntity{})
+//
+// Fields of embedded struct, e.g. ID or CreatedAt, are mapped from and into fields
+// with the same names of the other struct, unless it has a field named as embedded struct itself.
+func (m *Mapper) Flatten(embedded ...interface{}) {
+	for _, e := range embedded {
+		if tp := structType(reflect.TypeOf(e)); tp != nil {
+			m.flattened = append(m.flattened, tp)
+		}
+	}
+
+	m.resetPlans()
+}
+
+// flattenedField returns struct type of field at index of struct tp if it's embedded and flattened,
+// either with Flatten or with `mapper:",squash"` tag option.
+func (m *Mapper) flattenedField(tp reflect.Type, index int) (reflect.Type, bool) {
+	field := tp.Field(index)
+	embedded := structType(field.Type)
+	if !field.Anonymous || embedded == nil {
+		return nil, false
+	}
+
+	if _, opts, err := parseTag(field, ""); err == nil && opts.has(tagSquash) {
+		return embedded, true
+	}
+
+	for _, flattened := range m.flattened {
+		if flattened == embedded {
+			return embedded, true
+		}
+	}
+
+	return nil, false
+}
+
+// flattenedSourceOp returns op mapping field with name of flattened embedded struct of source into toType.
+func (m *Mapper) flattenedSourceOp(mappingInfo structMappingInfo, fromFields *structFields, name string, toType reflect.Type,
+	toOpts tagOptions,
+) (fieldOp, bool) {
+	for _, meta := range fromFields.list {
+		embedded, ok := m.flattenedField(mappingInfo.from, meta.index)
+		if !ok || !meta.exported {
+			continue
+		}
+
+		embeddedInfo := mappingInfo
+		embeddedInfo.from = embedded
+		op, ok := m.newFieldOp(embeddedInfo, mappingInfo.fields(embedded), name, toType, toOpts)
+		if ok {
+			op.fromPath = []int{meta.index, op.fromIndex}
+			return op, true
+		}
+	}
+
+	return fieldOp{}, false
+}
+
+// flattenedDestOps returns ops mapping source fields into fields of flattened embedded struct
+// at toMeta of destination.
+func (m *Mapper) flattenedDestOps(mappingInfo structMappingInfo, fromFields *structFields, toMeta *fieldMeta,
+	embedded reflect.Type,
+) []fieldOp {
+	var ops []fieldOp
+	for _, meta := range mappingInfo.fields(embedded).list {
+		if !meta.exported {
+			continue
+		}
+
+		if meta.err != nil {
+			op := invalidTagOp(&meta, embedded.Field(meta.index).Type)
+			op.toIndex, op.toPath = toMeta.index, []int{toMeta.index, meta.index}
+			ops = append(ops, op)
+			continue
+		}
+
+		op, ok := m.newFieldOp(mappingInfo, fromFields, meta.name, embedded.Field(meta.index).Type, meta.opts)
+		if ok {
+			op.toIndex, op.toPath = toMeta.index, []int{toMeta.index, meta.index}
+			op.setDestTags(&meta)
+			ops = append(ops, op)
+		}
+	}
+
+	return ops
+}
+
+// fieldByPath returns field of struct val by index path, allocating nil embedded pointers.
+func fieldByPath(val reflect.Value, path []int) reflect.Value {
+	for i, index := range path {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+
+			val = val.Elem()
+		}
+
+		val = val.Field(index)
+	}
+
+	return val
+}
+
+// typeByPath returns type of field of struct tp by index path.
+func typeByPath(tp reflect.Type, path []int) (reflect.Type, bool) {
+	for _, index := range path {
+		tp = structType(tp)
+		if tp == nil || index < 0 || index >= tp.NumField() {
+			return nil, false
+		}
+
+		tp = tp.Field(index).Type
+	}
+
+	return tp, true
+}
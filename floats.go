@@ -0,0 +1,97 @@
+package automapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// SpecialFloatError is returned in WithSpecialFloatCheck mode when NaN or ±Inf is mapped
+// into float field, since such values can't be encoded, e.g. as JSON.
+type SpecialFloatError struct {
+	// Path is a path of destination field, e.g. "Items[0].Price".
+	Path  string
+	Value float64
+}
+
+func (e *SpecialFloatError) Error() string {
+	return fmt.Sprintf("%s: field %s: %v", ErrSpecialFloat, e.Path, e.Value)
+}
+
+func (e *SpecialFloatError) Unwrap() error {
+	return ErrSpecialFloat
+}
+
+func (e *SpecialFloatError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *SpecialFloatError) fieldPath() string {
+	return e.Path
+}
+
+// WithSpecialFloatCheck makes NaN and ±Inf values mapped into float fields fail with *SpecialFloatError,
+// whether they are copied, converted or returned by converters. By default they are passed through.
+func WithSpecialFloatCheck() Option {
+	return func(o *options) {
+		o.specialFloats = specialFloatsFail
+	}
+}
+
+// WithSpecialFloatSubstitute replaces NaN and ±Inf values mapped into float fields with value, e.g. 0.
+func WithSpecialFloatSubstitute(value float64) Option {
+	return func(o *options) {
+		o.specialFloats = specialFloatsSubstitute
+		o.floatSubstitute = value
+	}
+}
+
+// specialFloatPolicy sets mapping of NaN and ±Inf values.
+type specialFloatPolicy int
+
+const (
+	specialFloatsPass specialFloatPolicy = iota
+	specialFloatsFail
+	specialFloatsSubstitute
+)
+
+// checkSpecialFloat applies special float policy to mapped float or pointer to float destination to.
+func checkSpecialFloat(st *mapState, to reflect.Value) error {
+	if to.Kind() == reflect.Ptr && !to.IsNil() {
+		to = to.Elem()
+	}
+
+	if !isFloat(to.Kind()) || (!math.IsNaN(to.Float()) && !math.IsInf(to.Float(), 0)) {
+		return nil
+	}
+
+	if st.opts.specialFloats == specialFloatsFail {
+		return &SpecialFloatError{Value: to.Float()}
+	}
+
+	to.SetFloat(st.opts.floatSubstitute)
+	return nil
+}
+
+// hasFloatElems reports whether tp is slice, array or map of floats, which elements are checked one by one.
+func hasFloatElems(tp reflect.Type) bool {
+	switch tp.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return isFloat(tp.Elem().Kind())
+	default:
+		return false
+	}
+}
+
+// mapFloatElems maps float elements of fromVal into to element by element, checking them,
+// so that substitutes aren't set into elements shared with source.
+func (m *Mapper) mapFloatElems(st *mapState, fromVal, toVal reflect.Value) error {
+	switch fromVal.Kind() {
+	case reflect.Slice:
+		return m.mapSlicesFunc(st, fromVal, toVal)
+	case reflect.Array:
+		return m.mapArraysFunc(st, fromVal, toVal)
+	default:
+		return m.mapMapsFunc(st, fromVal, toVal)
+	}
+}
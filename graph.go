@@ -0,0 +1,85 @@
+package automapper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WriteDOT writes graph of mappings built so far in Graphviz DOT format, so that mapping topology
+// of a service can be rendered, e.g. with `dot -Tsvg`. Nodes are types, solid edges are plans
+// of struct pairs labeled with their numbers of fields and modes, edges of plans with fields
+// which can't be mapped are red. Dashed edges are registered converters labeled with their names.
+func (m *Mapper) WriteDOT(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteString("digraph automapper {\n\tnode [shape=box];\n")
+	nodes := make(map[reflect.Type]bool)
+	node := func(tp reflect.Type) {
+		if !nodes[tp] {
+			nodes[tp] = true
+			fmt.Fprintf(buf, "\t%s;\n", dotQuote(tp.String()))
+		}
+	}
+
+	mappings := m.Mappings()
+	converters := m.Converters()
+	for _, mapping := range mappings {
+		node(mapping.From)
+		node(mapping.To)
+	}
+
+	for _, converter := range converters {
+		node(converter.From)
+		node(converter.To)
+	}
+
+	for _, mapping := range mappings {
+		label, attrs := mappingLabel(mapping), ""
+		for _, field := range mapping.Fields {
+			if field.Err != nil {
+				attrs = ", color=red"
+				break
+			}
+		}
+
+		fmt.Fprintf(buf, "\t%s -> %s [label=%s%s];\n",
+			dotQuote(mapping.From.String()), dotQuote(mapping.To.String()), dotQuote(label), attrs)
+	}
+
+	for _, converter := range converters {
+		fmt.Fprintf(buf, "\t%s -> %s [label=%s, style=dashed];\n",
+			dotQuote(converter.From.String()), dotQuote(converter.To.String()), dotQuote(converter.Name))
+	}
+
+	buf.WriteString("}\n")
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("error in Flush: %w", err)
+	}
+
+	return nil
+}
+
+// mappingLabel returns edge label of mapping, e.g. "3 fields, protobuf".
+func mappingLabel(mapping MappingInfo) string {
+	label := fmt.Sprintf("%d fields", len(mapping.Fields))
+	if len(mapping.Fields) == 1 {
+		label = "1 field"
+	}
+
+	if mapping.Protobuf {
+		label += ", protobuf"
+	}
+
+	if mapping.GraphQL {
+		label += ", graphql"
+	}
+
+	return label
+}
+
+// dotQuote returns DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
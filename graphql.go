@@ -0,0 +1,53 @@
+package automapper
+
+import "reflect"
+
+// NilPolicy sets how zero values are mapped between optional pointer fields and plain fields.
+type NilPolicy int
+
+const (
+	// NilSkip leaves destination untouched if source is nil or zero, like every zero source field,
+	// so zero scalars are mapped into nil pointers.
+	NilSkip NilPolicy = iota
+	// NilZero maps nil pointers into zero values, and zero values into pointers to them,
+	// e.g. "" into non-nil *string, so clients see empty values instead of nulls.
+	NilZero
+)
+
+// WithGraphQL enables conventions of models generated by gqlgen:
+//   - fields without mapper tag names are matched by `graphql:"name"` tag names;
+//   - optional *T and *[]T fields map into T and []T fields and back with nil policy;
+//
+// e.g. m := automapper.New(automapper.WithGraphQL(automapper.NilZero)).
+func WithGraphQL(policy NilPolicy) Option {
+	return func(o *options) {
+		o.graphql = true
+		o.nilPolicy = policy
+	}
+}
+
+// fields returns metadata of struct type fields named according to mapping modes.
+func (info structMappingInfo) fields(tp reflect.Type) *structFields {
+	if info.graphql {
+		return cachedTaggedFields(tp, "graphql")
+	}
+
+	return cachedFields(tp)
+}
+
+// setZeroOptional maps zero from into to with NilZero policy: nil pointer into zero value
+// and zero value into pointer to zero value. Other values are skipped.
+func setZeroOptional(from, to reflect.Value) {
+	switch {
+	case from.Kind() == reflect.Ptr && to.Kind() != reflect.Ptr && isOptionalKind(to.Kind()):
+		to.Set(reflect.Zero(to.Type()))
+	case from.Kind() != reflect.Ptr && to.Kind() == reflect.Ptr && isOptionalKind(from.Kind()) &&
+		isOptionalKind(to.Type().Elem().Kind()):
+		to.Set(reflect.New(to.Type().Elem()))
+	}
+}
+
+// isOptionalKind reports whether values of kind are optional as pointers, i.e. scalars and slices.
+func isOptionalKind(kind reflect.Kind) bool {
+	return kind == reflect.String || kind == reflect.Bool || kind == reflect.Slice || isNumeric(kind)
+}
@@ -0,0 +1,95 @@
+package automapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Include merges configuration of other into m, so that libraries can ship pre-configured mappers
+// which applications compose: converters, named converters, factories and pairs declared with CreateMap.
+// Registrations of the same types or names with different functions conflict, Include returns
+// errors of all conflicts wrapping ErrConflict and leaves m unchanged then. Later registrations
+// of other aren't seen by m.
+func (m *Mapper) Include(other *Mapper) error {
+	if other == nil {
+		return fmt.Errorf("%w: included mapper", ErrNilValue)
+	}
+
+	if other == m {
+		return nil
+	}
+
+	converters, own := other.converterTable(), m.converterTable()
+	var errs []error
+	for info, converter := range converters {
+		if current, ok := own[info]; ok && !sameFunc(current, converter) {
+			errs = append(errs, fmt.Errorf("%w: converter '%s -> %s'", ErrConflict, info.from, info.to))
+		}
+	}
+
+	for name, converter := range other.named {
+		if current, ok := m.named[name]; ok && !sameFunc(current, converter) {
+			errs = append(errs, fmt.Errorf("%w: named converter '%s'", ErrConflict, name))
+		}
+	}
+
+	for tp, factory := range other.factories {
+		if current, ok := m.factories[tp]; ok && !sameFunc(current, factory) {
+			errs = append(errs, fmt.Errorf("%w: factory of '%s'", ErrConflict, tp))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for info, converter := range converters {
+		m.storeConverter(info, converter)
+	}
+
+	for name, converter := range other.named {
+		if m.named == nil {
+			m.named = make(map[string]reflect.Value)
+		}
+
+		m.named[name] = converter
+	}
+
+	for tp, factory := range other.factories {
+		if m.factories == nil {
+			m.factories = make(map[reflect.Type]reflect.Value)
+		}
+
+		m.factories[tp] = factory
+	}
+
+	other.mu.RLock()
+	pairs := append([]structMappingInfo(nil), other.pairs...)
+	other.mu.RUnlock()
+	m.mu.Lock()
+	for _, pair := range pairs {
+		if !containsPair(m.pairs, pair) {
+			m.pairs = append(m.pairs, pair)
+		}
+	}
+	m.mu.Unlock()
+
+	m.resetPlans()
+	return nil
+}
+
+// sameFunc reports whether funcs a and b are the same function.
+func sameFunc(a, b reflect.Value) bool {
+	return a.Type() == b.Type() && a.Pointer() == b.Pointer()
+}
+
+func containsPair(pairs []structMappingInfo, pair structMappingInfo) bool {
+	for _, declared := range pairs {
+		if declared == pair {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// implementation returns registered implementation of interface type to,
+// which fields match most fields of from struct type, nil if there's none.
+// Implementations registered earlier win ties.
+func (m *Mapper) implementation(from, to reflect.Type) reflect.Type {
+	fromStruct := structType(from)
+	if fromStruct == nil {
+		return nil
+	}
+
+	fromFields := cachedFields(fromStruct)
+	var best reflect.Type
+	bestScore := 0
+	for _, impl := range m.impls {
+		implStruct := structType(impl)
+		if implStruct == nil || !impl.Implements(to) {
+			continue
+		}
+
+		score := 0
+		for _, meta := range cachedFields(implStruct).list {
+			if _, ok := fromFields.field(meta.name); ok && meta.exported {
+				score++
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = impl, score
+		}
+	}
+
+	return best
+}
+
+// hasImplementations reports whether any registered implementation implements interface type tp.
+func (m *Mapper) hasImplementations(tp reflect.Type) bool {
+	for _, impl := range m.impls {
+		if structType(impl) != nil && impl.Implements(tp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapInterfacesFunc maps struct into interface destination: into struct pointed by value it holds,
+// or into new registered implementation chosen by source fields. Registered variants are mapped
+// into their counterparts. Interface sources are mapped
+// by their dynamic values, so heterogeneous []any slices are mapped element by element.
+func (m *Mapper) mapInterfacesFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if fromVal.Kind() == reflect.Interface {
+		fromVal = fromVal.Elem()
+	}
+
+	if v, toWrapper, ok := m.variantOf(fromVal.Type(), toVal.Type()); ok {
+		return m.mapVariant(st, fromVal, toVal, v, toWrapper)
+	}
+
+	if held, ok := heldStruct(toVal); ok {
+		return m.mapValue(st, fromVal, held)
+	}
+
+	impl := m.implementation(fromVal.Type(), toVal.Type())
+	if impl == nil {
+		return m.missingConverter(fromVal, toVal)
+	}
+
+	implVal := reflect.New(impl).Elem()
+	err := m.mapValue(st, fromVal, implVal)
+	if err != nil {
+		return fmt.Errorf("error in mapValue: %w", err)
+	}
+
+	toVal.Set(implVal)
+	return nil
+}
+
+// WithInterfaceCopies makes values held by source and destination fields of identical interface types
+// copied instead of shared: a converter registered from dynamic type into itself is used,
+// and structs pointed by held pointers are mapped into new structs. Other values are assigned as is.
+// Nil sources are skipped like nil pointers are.
+func WithInterfaceCopies() Option {
+	return func(o *options) {
+		o.copyInterfaces = true
+	}
+}
+
+// copyInterface sets copy of value held by interface from into to.
+func (m *Mapper) copyInterface(st *mapState, fromVal, toVal reflect.Value) error {
+	if fromVal.IsNil() {
+		toVal.Set(fromVal)
+		return nil
+	}
+
+	held := fromVal.Elem()
+	copied := reflect.New(held.Type()).Elem()
+	if converter, ok := m.converterTable()[converterInfo{from: held.Type(), to: held.Type()}]; ok {
+		err := m.callConverter(converter, held, copied)
+		if err != nil {
+			return err
+		}
+
+		toVal.Set(copied)
+		return nil
+	}
+
+	if held.Kind() != reflect.Ptr || held.IsNil() || held.Elem().Kind() != reflect.Struct {
+		toVal.Set(fromVal)
+		return nil
+	}
+
+	copied.Set(reflect.New(held.Type().Elem()))
+	err := m.mapStructsFunc(st, held.Elem(), copied.Elem())
+	if err != nil {
+		return err
+	}
+
+	toVal.Set(copied)
+	return nil
+}
+
+// hasInterfaceFields reports whether struct type tp has fields of interface types.
+func hasInterfaceFields(tp reflect.Type) bool {
+	for i := 0; i < tp.NumField(); i++ {
+		if tp.Field(i).Type.Kind() == reflect.Interface {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,180 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConverterInfo describes a registered converter.
+type ConverterInfo struct {
+	From, To reflect.Type
+	// Name is a name of converter function, e.g. "strconv.Itoa".
+	Name string
+}
+
+// Converters returns registered converters sorted by their types, e.g. to check wiring in tests
+// or to dump the registry. Converters composed with Compose have generated names.
+func (m *Mapper) Converters() []ConverterInfo {
+	table := m.converterTable()
+	converters := make([]ConverterInfo, 0, len(table))
+	for info, converter := range table {
+		converters = append(converters, ConverterInfo{From: info.from, To: info.to, Name: funcName(converter)})
+	}
+
+	sort.Slice(converters, func(i, j int) bool {
+		if from, other := converters[i].From.String(), converters[j].From.String(); from != other {
+			return from < other
+		}
+
+		return converters[i].To.String() < converters[j].To.String()
+	})
+
+	return converters
+}
+
+// MappingInfo describes a plan of mapping between two struct types built so far.
+type MappingInfo struct {
+	From, To reflect.Type
+	// Protobuf and GraphQL are modes the plan is built with.
+	Protobuf, GraphQL bool
+	Fields            []FieldPlan
+}
+
+// FieldPlan describes mapping of a destination field.
+type FieldPlan struct {
+	// Name is a name of destination field, e.g. "City" or "Address.City" for fields of flattened structs.
+	Name string
+	// Source describes source of the field: name of source field, e.g. "Address.City" for fields
+	// of flattened structs, getter, e.g. "GetName()", entry of remain map, e.g. "Extra[color]",
+	// or source fields collected by remain field, e.g. "Color, Size".
+	Source   string
+	From, To reflect.Type
+	// Strategy is a name of strategy, e.g. StrategyConverter, and Converter is a name of its converter.
+	Strategy, Converter string
+	// Err is returned on mapping of non-zero source, e.g. for missing converters.
+	Err error
+}
+
+// Mappings returns plans of struct type pairs built so far, sorted by their types,
+// so that tooling can show what the mapper learned. Plans are copies, which changes don't affect mapping.
+func (m *Mapper) Mappings() []MappingInfo {
+	if !m.frozen {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
+
+	mappings := make([]MappingInfo, 0, len(m.knownMappings))
+	for mappingInfo, plan := range m.knownMappings {
+		mapping := MappingInfo{
+			From:     mappingInfo.from,
+			To:       mappingInfo.to,
+			Protobuf: mappingInfo.protobuf,
+			GraphQL:  mappingInfo.graphql,
+			Fields:   make([]FieldPlan, 0, len(plan)),
+		}
+		for i := range plan {
+			mapping.Fields = append(mapping.Fields, m.fieldPlan(mappingInfo, &plan[i]))
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	sort.Slice(mappings, func(i, j int) bool {
+		if from, other := mappings[i].From.String(), mappings[j].From.String(); from != other {
+			return from < other
+		}
+
+		if to, other := mappings[i].To.String(), mappings[j].To.String(); to != other {
+			return to < other
+		}
+
+		return modeRank(mappings[i]) < modeRank(mappings[j])
+	})
+
+	return mappings
+}
+
+// modeRank orders mappings of the same types by their modes.
+func modeRank(mapping MappingInfo) int {
+	rank := 0
+	if mapping.Protobuf {
+		rank++
+	}
+
+	if mapping.GraphQL {
+		rank += 2
+	}
+
+	return rank
+}
+
+func (m *Mapper) fieldPlan(mappingInfo structMappingInfo, op *fieldOp) FieldPlan {
+	field := FieldPlan{
+		Name:     fieldNames(mappingInfo.to, op.toPath, op.toIndex),
+		Source:   fieldSource(mappingInfo.from, op),
+		From:     op.fromType,
+		To:       op.toType,
+		Strategy: m.names[op.strategy],
+		Err:      opErr(op),
+	}
+	if op.strategy == converterFunc && op.converter.IsValid() {
+		field.Converter = funcName(op.converter)
+	}
+
+	return field
+}
+
+// opErr returns error of mapping op, invalid tags first.
+func opErr(op *fieldOp) error {
+	if op.tagErr != nil {
+		return op.tagErr
+	}
+
+	return op.err
+}
+
+// fieldSource describes source of op in struct type from, see FieldPlan.
+func fieldSource(from reflect.Type, op *fieldOp) string {
+	switch {
+	case len(op.oneofs) > 0:
+		names := make([]string, 0, len(op.oneofs))
+		for i := range op.oneofs {
+			names = append(names, fieldSource(from, &op.oneofs[i].op))
+		}
+
+		return strings.Join(names, ", ")
+	case len(op.remain) > 0:
+		names := make([]string, 0, len(op.remain))
+		for _, field := range op.remain {
+			names = append(names, from.Field(field.index).Name)
+		}
+
+		return strings.Join(names, ", ")
+	case op.remainKey != "":
+		return fmt.Sprintf("%s[%s]", from.Field(op.fromIndex).Name, op.remainKey)
+	case op.getter >= 0:
+		return reflect.PointerTo(from).Method(op.getter).Name + "()"
+	case op.fromType == nil:
+		return ""
+	default:
+		return fieldNames(from, op.fromPath, op.fromIndex)
+	}
+}
+
+// fieldNames returns dot separated names of fields of struct type tp by index path, or name of field at index.
+func fieldNames(tp reflect.Type, path []int, index int) string {
+	if len(path) == 0 {
+		return tp.Field(index).Name
+	}
+
+	names := make([]string, 0, len(path))
+	for _, i := range path {
+		tp = structType(tp)
+		names = append(names, tp.Field(i).Name)
+		tp = tp.Field(i).Type
+	}
+
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,66 @@
+package automapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var (
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// WithJSONRawMessages enables mapping json.RawMessage fields into struct and map fields
+// by unmarshaling them, and struct and map fields into json.RawMessage by marshaling,
+// e.g. to map values decoded into generic JSON envelopes.
+func WithJSONRawMessages() Option {
+	return func(o *options) {
+		o.rawJSON = true
+	}
+}
+
+// isJSONNumberTarget reports whether json.Number is converted into tp by jsonNumbers strategy.
+func isJSONNumberTarget(tp reflect.Type) bool {
+	return tp != durationType && (isNumeric(tp.Kind()) || tp.Kind() == reflect.String)
+}
+
+// mapJSONNumberFunc parses json.Number into numeric or string destination.
+func (m *Mapper) mapJSONNumberFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	parsed, err := parseString(fromVal.String(), toVal.Type())
+	if err != nil {
+		return err
+	}
+
+	toVal.Set(parsed)
+	return nil
+}
+
+// isRawJSON reports whether from is mapped into to via encoding/json.
+func isRawJSON(from, to reflect.Type) bool {
+	return (from == rawMessageType && isJSONObject(to)) || (to == rawMessageType && isJSONObject(from))
+}
+
+func isJSONObject(tp reflect.Type) bool {
+	return isStructOrPtrToStruct(tp) || tp.Kind() == reflect.Map
+}
+
+// mapRawJSON unmarshals json.RawMessage into to, or marshals from into json.RawMessage.
+func mapRawJSON(fromVal, toVal reflect.Value) error {
+	if fromVal.Type() == rawMessageType {
+		err := json.Unmarshal(fromVal.Bytes(), toVal.Addr().Interface())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrConverter, err)
+		}
+
+		return nil
+	}
+
+	raw, err := json.Marshal(fromVal.Interface())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConverter, err)
+	}
+
+	toVal.SetBytes(raw)
+	return nil
+}
@@ -1,10 +1,14 @@
 package automapper
 
 import (
+	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -13,42 +17,242 @@ var (
 	ErrMissingConverter          = errors.New("converter is missing for types")
 	ErrConverter                 = errors.New("converter error")
 	ErrConverterErrorUnknownType = errors.New("converter 2nd return value cannot be converted to error")
+	ErrBadConverterSignature     = errors.New("bad converter signature")
+	ErrUnsupportedPatch          = errors.New("patch must be a struct or map[string]interface{}")
+	ErrInvalidTag                = errors.New("invalid mapper tag")
+	ErrComposition               = errors.New("converters cannot be composed")
+	ErrDuplicateStrategy         = errors.New("strategy is already registered")
+	ErrUnknownStrategy           = errors.New("strategy is not registered")
+	ErrLossyConversion           = errors.New("lossy numeric conversion")
+	ErrMaxDepth                  = errors.New("max mapping depth exceeded")
+	ErrNotAStruct                = errors.New("value is not a struct")
+	ErrNilValue                  = errors.New("value is nil")
+	ErrUnsupportedMapping        = errors.New("mapping is not supported for types")
+	ErrNotAOneofWrapper          = errors.New("value is not a oneof wrapper")
+	ErrNotAFactory               = errors.New("value is not a factory function")
+	ErrNilSource                 = errors.New("source is nil")
+	ErrSpecialFloat              = errors.New("special float value")
+	ErrMissingSource             = errors.New("required source is missing")
+	ErrMissingChain              = errors.New("chain of declared mappings is missing for types")
+	ErrConflict                  = errors.New("configurations conflict")
+	ErrUnusedSource              = errors.New("source field is not mapped")
+	ErrInvalidOption             = errors.New("option is invalid for the call")
 )
 
+// pathError is an error which reports path of destination field it happened at.
+type pathError interface {
+	error
+	prependPath(segment string)
+	fieldPath() string
+}
+
+// withFieldPath prepends field path segment, e.g. field name or "[1]" index, to path of err.
+// Errors without path are wrapped, so that path of any field error is known.
+func withFieldPath(err error, segment string) error {
+	var pathErr pathError
+	if errors.As(err, &pathErr) {
+		pathErr.prependPath(segment)
+		return err
+	}
+
+	return &fieldError{path: segment, err: err}
+}
+
+// fieldError adds destination field path to error, keeping its message.
+type fieldError struct {
+	path string
+	err  error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+func (e *fieldError) prependPath(segment string) {
+	e.path = joinPath(segment, e.path)
+}
+
+func (e *fieldError) fieldPath() string {
+	return e.path
+}
+
+// joinPath joins path segment and path in "Items[0].Name" format.
+func joinPath(segment, path string) string {
+	if path != "" && !strings.HasPrefix(path, "[") {
+		segment += "."
+	}
+
+	return segment + path
+}
+
 type converterInfo struct {
 	from, to reflect.Type
 }
 
 type structMappingInfo struct {
 	from, to reflect.Type
-}
-
-type fieldMappingInfo struct {
-	fromIndex, toIndex int
-	mapperFunc         mapperFunc
+	// protobuf mode changes field sources
+	protobuf bool
+	// graphql mode changes field names, see WithGraphQL
+	graphql bool
 }
 
 // Mapper maps struct values.
+//
+// Map and its variants are safe for concurrent use. Set and Compose may be called concurrently
+// with mapping, e.g. by lazily initialized modules: calls started after Set returns use the converter.
+// Other configuration methods must be called before the Mapper is used concurrently.
 type Mapper struct {
-	mu            sync.Mutex
-	converters    map[converterInfo]reflect.Value
-	strats        map[supportedType]mapperFunc
-	knownMappings map[structMappingInfo][]fieldMappingInfo
+	mu sync.RWMutex
+	// converters are replaced on registration, convertersMu serializes registrations
+	converters   atomic.Pointer[converterTable]
+	convertersMu sync.Mutex
+	strats       map[supportedType]mapperFunc
+	matchers     map[supportedType]matchFunc
+	// order is precedence of strategies, custom ones included
+	order         []supportedType
+	names         map[supportedType]string
+	knownMappings map[structMappingInfo][]fieldOp
+	// planGeneration is incremented when plans are reset
+	planGeneration uint64
+	// planLRU orders keys of knownMappings by use if cache is bounded, see WithPlanCacheSize
+	planLRU       *list.List
+	planElems     map[structMappingInfo]*list.Element
+	importedPlans map[importedPlanKey]importedPlan
+	impls         []reflect.Type
+	variants      []variant
+	flattened     []reflect.Type
+	// pairs are declared with CreateMap
+	pairs     []structMappingInfo
+	opts      options
+	fallback  Fallback
+	translate ErrorTranslator
+	stats     stats
+	// cloneMethod is a name of method copying fields of identical types
+	cloneMethod string
+	// factories are funcs creating values of types by type, see SetFactory
+	factories map[reflect.Type]reflect.Value
+	// moneys are codecs of money types, see RegisterMoney
+	moneys map[reflect.Type]moneyCodec
+	// named are converters selected by field tags, see SetNamed
+	named map[string]reflect.Value
+	// pools are sources of pointer destinations by type of their values, see SetPool
+	pools map[reflect.Type]Pool
+	// pooledTypes caches holdsPooled results by reflect.Type, it's reset by SetPool
+	pooledTypes *sync.Map
+	allocHook   AllocationHook
+	// frozen mappers of snapshots read plans without locks and don't count stats
+	frozen bool
+}
+
+// mapState holds state of a single Map call passed down to strategies.
+type mapState struct {
+	opts *options
+	// sels restrict mapped fields, field must be selected by all of them.
+	sels []*fieldSelector
+	// visited are destination pointers of mapped source pointers,
+	// so that cyclic and shared structs are mapped into the same graph.
+	visited map[visitKey]reflect.Value
+	// depth is nesting level of struct being mapped.
+	depth int
+	// ctx is checked while mapping collections, nil if call has no context.
+	ctx context.Context
+	// path and parent are destination path and pointer to struct being mapped,
+	// they are tracked only if allocations are hooked, see SetAllocationHook,
+	// path is also tracked with changes.
+	path   []string
+	parent reflect.Value
+	// changes are paths of destination fields written with different values, nil if they aren't tracked, see MapTrack.
+	changes *[]string
+}
+
+// cancelCheckInterval is a number of collection elements mapped between context checks.
+const cancelCheckInterval = 1024
+
+// checkContext returns context error before mapping every cancelCheckInterval-th element i.
+func (s *mapState) checkContext(i int) error {
+	if s.ctx == nil || i%cancelCheckInterval != 0 {
+		return nil
+	}
+
+	return s.ctx.Err()
 }
 
-type fieldInfo struct {
-	index int
-	val   reflect.Value
+type visitKey struct {
+	from uintptr
+	to   reflect.Type
+}
+
+// newMapState returns state for a call with opts applied over Mapper options.
+// State must be released with releaseMapState after the call.
+func (m *Mapper) newMapState(opts []Option) *mapState {
+	st := acquireMapState()
+	m.opts.copyTo(st.opts)
+	for _, opt := range opts {
+		opt(st.opts)
+	}
+
+	if st.opts.planCacheSize != m.opts.planCacheSize || len(st.opts.sourceNames) != len(m.opts.sourceNames) ||
+		len(st.opts.destNames) != len(m.opts.destNames) {
+		st.opts.optionErr = fmt.Errorf("%w: name transformers and plan cache size must be passed to New", ErrInvalidOption)
+	}
+
+	for _, sel := range []*fieldSelector{newFieldSelector(st.opts.only, false), newFieldSelector(st.opts.except, true)} {
+		if sel != nil {
+			st.sels = append(st.sels, sel)
+		}
+	}
+
+	return st
+}
+
+// field returns state for mapping of the nested field.
+func (s *mapState) field(name string) (*mapState, bool) {
+	if len(s.sels) == 0 {
+		return s, true
+	}
+
+	fieldState := &mapState{opts: s.opts, visited: s.visited, depth: s.depth, ctx: s.ctx, path: s.path, parent: s.parent, changes: s.changes}
+	for _, sel := range s.sels {
+		child, ok := sel.child(name)
+		if !ok {
+			return nil, false
+		}
+
+		if child != nil {
+			fieldState.sels = append(fieldState.sels, child)
+		}
+	}
+
+	return fieldState, true
 }
 
 // New returns new Mapper.
-func New() *Mapper {
+// Options apply to every Map call, options passed to Map are applied over them.
+func New(opts ...Option) *Mapper {
 	m := &Mapper{
-		mu:            sync.Mutex{},
-		converters:    make(map[converterInfo]reflect.Value),
-		knownMappings: make(map[structMappingInfo][]fieldMappingInfo),
+		mu:            sync.RWMutex{},
+		knownMappings: make(map[structMappingInfo][]fieldOp),
+		planLRU:       list.New(),
+		planElems:     make(map[structMappingInfo]*list.Element),
+		cloneMethod:   defaultCloneMethod,
+	}
+	for _, opt := range opts {
+		opt(&m.opts)
 	}
+
 	m.strats = m.initStrategies()
+	m.matchers = m.initMatchers()
+	m.order = append([]supportedType(nil), builtinOrder...)
+	m.names = make(map[supportedType]string, len(strategyNames))
+	for strategy, name := range strategyNames {
+		m.names[strategy] = name
+	}
+
 	return m
 }
 
@@ -57,137 +261,262 @@ func New() *Mapper {
 //  func(in int) string
 //  func(in string) (int, error)
 // Set will make the Mapper use the converter function to map in-type to out-type
-// every time the Mapper comes across one.
+// every time the Mapper comes across one, including elements of slices, arrays and maps,
+// e.g. func(A) B maps []A into []B, [N]A into [N]B, map[K]A into map[K]B and map[A]V into map[B]V.
+// Pointers are unwrapped and allocated around converted values, so it also maps []*A into []B and []A into []*B.
+// Converters accepting or returning pointers, e.g. func(*Money) string or func(int) *string, also map
+// values of pointed types, nil results leave destinations unchanged.
+// Method values, e.g. formatter.Format, are converters too. Functions of other forms,
+// e.g. variadic ones, are rejected with ErrBadConverterSignature.
 func (m *Mapper) Set(converter interface{}) error {
 	fn := reflect.TypeOf(converter)
-	if fn.Kind() != reflect.Func {
-		return ErrNotAFn
+	if err := validateConverter(fn); err != nil {
+		return err
 	}
 
-	m.converters[converterInfo{from: fn.In(0), to: fn.Out(0)}] = reflect.ValueOf(converter)
+	m.storeConverter(converterInfo{from: fn.In(0), to: fn.Out(0)}, reflect.ValueOf(converter))
+	m.resetPlans()
 	return nil
 }
 
+// Fallback converts from into to when mapper has no converter for their types.
+// It reports whether from was handled, unhandled values fail with ErrMissingConverter.
+type Fallback func(from, to reflect.Value) (handled bool, err error)
+
+// SetFallback sets last-resort fallback, which is called instead of returning ErrMissingConverter,
+// e.g. to convert values via JSON round-trip. Fallback errors are wrapped with ErrConverter.
+func (m *Mapper) SetFallback(fallback Fallback) {
+	m.fallback = fallback
+}
+
+// missingConverter passes from and to to fallback, returning ErrMissingConverter if it isn't handled.
+func (m *Mapper) missingConverter(from, to reflect.Value) error {
+	if m.fallback != nil {
+		handled, err := m.fallback(from, to)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrConverter, err)
+		}
+
+		if handled {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, from.Type(), to.Type())
+}
+
+// ErrorTranslator rewrites error of Map, fieldPath is a path of destination field
+// error happened at in "Items[0].Name" format, empty if error is not related to a field.
+type ErrorTranslator func(fieldPath string, err error) error
+
+// SetErrorTranslator sets translator of errors returned by Map, e.g. to turn converter errors
+// into user-facing messages like "birth_date must be YYYY-MM-DD". Field paths consist of field names
+// or mapper tags of destination fields. Errors translated into nil are returned untranslated.
+func (m *Mapper) SetErrorTranslator(translate ErrorTranslator) {
+	m.translate = translate
+}
+
+// translateError classifies err with *Error and applies error translator to it.
+func (m *Mapper) translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapErr := newError(err)
+	if m.translate == nil {
+		return mapErr
+	}
+
+	if translated := m.translate(mapErr.Path, mapErr); translated != nil {
+		return translated
+	}
+
+	return mapErr
+}
+
 // Map maps two structs or two slices of structs.
-func (m *Mapper) Map(from, to interface{}) error {
-	typeFrom := reflect.TypeOf(from)
-	typeTo := reflect.TypeOf(to)
+// Options apply to this call only. Errors are *Error, unless translated with SetErrorTranslator.
+// Arguments must be non-nil pointers (source may also be a string map), otherwise ErrNotAPtr
+// or ErrNilValue is returned, ErrUnsupportedMapping is returned for types Map can't map.
+// Nil sources fail with *NilSourceError, which is both ErrNilSource and ErrNilValue.
+func (m *Mapper) Map(from, to interface{}, opts ...Option) error {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	return m.translateError(m.mapValues(st, from, to))
+}
+
+// MapCtx maps like Map, but stops mapping of slices, arrays and maps with ctx.Err()
+// once ctx is done, so that large collections of cancelled requests aren't mapped to the end.
+// Context is checked every 1024 elements.
+func (m *Mapper) MapCtx(ctx context.Context, from, to interface{}, opts ...Option) error {
+	if err := ctx.Err(); err != nil {
+		return m.translateError(err)
+	}
+
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	st.ctx = ctx
+	return m.translateError(m.mapValues(st, from, to))
+}
+
+// MapMasked maps two structs or two slices of structs like Map, but only fields
+// listed in mask are mapped. Mask paths have google.protobuf.FieldMask format,
+// see Only for details. Empty mask maps every field.
+func (m *Mapper) MapMasked(from, to interface{}, mask []string, opts ...Option) error {
+	return m.Map(from, to, append([]Option{Only(mask...)}, opts...)...)
+}
+
+func (m *Mapper) mapValues(st *mapState, from, to interface{}) error {
+	if err := st.opts.err(); err != nil {
+		return err
+	}
+
 	valFrom := reflect.ValueOf(from)
 	valTo := reflect.ValueOf(to)
+	err := validateArgs(valFrom, valTo)
+	if err != nil {
+		return err
+	}
+
+	// pointer chains are unwrapped to a single pointer, e.g. **T into *T,
+	// and destination chain is allocated
+	for valFrom.Kind() == reflect.Ptr && valFrom.Type().Elem().Kind() == reflect.Ptr {
+		if valFrom.Elem().IsNil() {
+			return &NilSourceError{}
+		}
 
-	if (typeFrom.Kind() == reflect.Ptr && typeFrom.Elem().Kind() == reflect.Slice && isStructOrPtrToStruct(typeFrom.Elem().Elem())) &&
-		(typeTo.Kind() == reflect.Ptr && typeTo.Elem().Kind() == reflect.Slice && isStructOrPtrToStruct(typeTo.Elem().Elem())) {
-		return m.mapSlicesFunc(valFrom.Elem(), valTo.Elem())
+		valFrom = valFrom.Elem()
 	}
 
-	if isStructOrPtrToStruct(typeFrom) && isStructOrPtrToStruct(typeTo) {
-		return m.mapStructs(valFrom.Elem(), valTo.Elem())
+	for valTo.Kind() == reflect.Ptr && !valTo.IsNil() && valTo.Type().Elem().Kind() == reflect.Ptr {
+		if valTo.Elem().IsNil() {
+			valTo.Elem().Set(reflect.New(valTo.Type().Elem().Elem()))
+		}
+
+		valTo = valTo.Elem()
 	}
 
-	return nil
-}
+	typeFrom := valFrom.Type()
+	typeTo := valTo.Type()
 
-// from, to must be struct values.
-func (m *Mapper) mapStructs(from, to reflect.Value) error {
-	if !from.IsValid() {
-		return nil
+	if typeFrom.Kind() == reflect.Ptr && typeTo.Kind() == reflect.Ptr && m.matchers[slices](typeFrom.Elem(), typeTo.Elem()) {
+		return m.mapSlicesFunc(st, valFrom.Elem(), valTo.Elem())
 	}
 
-	m.mu.Lock()
-	mappingInfo := structMappingInfo{from: from.Type(), to: to.Type()}
-	if knownMapping, ok := m.knownMappings[mappingInfo]; ok {
-		err := m.mapKnownStruct(knownMapping, from, to)
-		if err != nil {
-			return err
+	if isStructOrPtrToStruct(typeFrom) && isStructOrPtrToStruct(typeTo) {
+		// root is visited, so that cycles through it lead back to destination
+		st.visited[visitKey{from: valFrom.Pointer(), to: typeTo}] = valTo
+		return m.mapStructs(st, valFrom.Elem(), valTo.Elem())
+	}
+
+	if typeFrom.Kind() == reflect.Ptr && typeTo.Kind() == reflect.Ptr {
+		mappingType := m.detectMappingType(typeFrom.Elem(), typeTo.Elem())
+		if mappingType == fromRecords || mappingType == toRecords {
+			return m.strats[mappingType](st, valFrom.Elem(), valTo.Elem())
 		}
 	}
 
-	m.knownMappings[mappingInfo] = make([]fieldMappingInfo, 0)
-	m.mu.Unlock()
+	if valFrom.Kind() == reflect.Ptr {
+		valFrom = valFrom.Elem()
+	}
 
-	fromFields, toFields := getFieldInfo(from, to)
-	for name, fromVal := range fromFields {
-		toVal, ok := toFields[name]
-		if !ok {
-			continue
-		}
+	if isStringMap(valFrom.Type()) && isStructOrPtrToStruct(typeTo) {
+		return m.mapStringMap(st, valFrom, valTo.Elem())
+	}
 
-		mappingType := m.detectMappingType(fromVal, toVal)
-		if mappingType != unsupported {
-			err := m.strats[mappingType](fromVal.val, toVal.val)
-			if err != nil {
-				return err
-			}
-
-			m.mu.Lock()
-			m.knownMappings[mappingInfo] = append(m.knownMappings[mappingInfo], fieldMappingInfo{
-				fromIndex:  fromVal.index,
-				toIndex:    toVal.index,
-				mapperFunc: m.strats[mappingType],
-			})
-			m.mu.Unlock()
+	return fmt.Errorf("%w '%s -> %s'", ErrUnsupportedMapping, typeFrom, typeTo)
+}
 
-			continue
-		}
+// validateArgs checks that destination is a non-nil pointer,
+// and source is a non-nil pointer or a map.
+func validateArgs(from, to reflect.Value) error {
+	if !to.IsValid() || to.Kind() != reflect.Ptr {
+		return ErrNotAPtr
+	}
+
+	if !from.IsValid() || (from.Kind() == reflect.Ptr && from.IsNil()) {
+		return &NilSourceError{}
+	}
+
+	if to.IsNil() {
+		return ErrNilValue
+	}
 
-		return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.val.Type(), toVal.val.Type())
+	if from.Kind() != reflect.Ptr && from.Kind() != reflect.Map {
+		return ErrNotAPtr
 	}
 
 	return nil
 }
 
-func getFieldInfo(from, to reflect.Value) (fromFields, toFields map[string]fieldInfo) {
-	fromFields = make(map[string]fieldInfo)
-	toFields = make(map[string]fieldInfo)
-	for i := 0; i < from.NumField(); i++ {
-		// skip zero or nil values
-		fieldVal := from.Field(i)
-		if fieldVal.IsZero() || (fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil()) {
-			continue
-		}
+// from, to must be struct values.
+func (m *Mapper) mapStructs(st *mapState, from, to reflect.Value) error {
+	if !from.IsValid() {
+		return nil
+	}
 
-		fieldType := from.Type().Field(i)
-		name := fieldType.Name
-		mapperTag, ok := fieldType.Tag.Lookup("mapper")
-		if ok && mapperTag != "" {
-			name = mapperTag
-		}
+	st.depth++
+	defer func() { st.depth-- }()
+	if st.opts.maxDepth > 0 && st.depth > st.opts.maxDepth {
+		return &DepthError{MaxDepth: st.opts.maxDepth}
+	}
 
-		fromFields[name] = fieldInfo{
-			index: i,
-			val:   fieldVal,
+	if m.canCopyStruct(st, from, to) {
+		to.Set(from)
+		return nil
+	}
+
+	plan := m.structPlan(st, from.Type(), to.Type())
+	if st.opts.strictSource {
+		if err := checkStrictSource(st, from.Type(), to.Type(), plan); err != nil {
+			return err
 		}
 	}
 
-	for i := 0; i < to.NumField(); i++ {
-		fieldVal := to.Field(i)
-		if !fieldVal.CanSet() {
+	if st.opts.unsafeCopy && canUnsafeCopy(st, from, to, plan) && !m.holdsPooled(from.Type()) {
+		unsafeCopy(from, to)
+		return nil
+	}
+
+	parent := m.enterStruct(st, to)
+	defer func() { st.parent = parent }()
+	for i := range plan {
+		op := &plan[i]
+		m.enterPath(st, op.name)
+		fieldState, ok := st.field(op.name)
+		if !ok {
+			m.leavePath(st)
 			continue
 		}
 
-		fieldType := to.Type().Field(i)
-		name := fieldType.Name
-		mapperTag, ok := fieldType.Tag.Lookup("mapper")
-		if ok && mapperTag != "" {
-			name = mapperTag
+		err := m.execFieldOp(fieldState, op, from, op.target(to))
+		m.leavePath(st)
+		if err != nil {
+			return withFieldPath(err, op.name)
 		}
+	}
 
-		toFields[name] = fieldInfo{
-			index: i,
-			val:   fieldVal,
-		}
+	if st.opts.openAPI {
+		return m.mapAdditionalProperties(st, from, to)
 	}
 
-	return fromFields, toFields
+	return nil
 }
 
-func (m *Mapper) mapKnownStruct(mappingInfo []fieldMappingInfo, from, to reflect.Value) error {
-	for _, fieldInfo := range mappingInfo {
-		err := fieldInfo.mapperFunc(from.Field(fieldInfo.fromIndex), to.Field(fieldInfo.toIndex))
-		if err != nil {
-			return err
-		}
+// canCopyStruct reports whether from can be copied into to as a whole
+// instead of field by field, the same way fields of identical types are.
+// Zero destinations only are copied, so that zero source fields don't overwrite destination.
+func (m *Mapper) canCopyStruct(st *mapState, from, to reflect.Value) bool {
+	if from.Type() != to.Type() || !copyAllowed(st, from, to) || m.holdsPooled(from.Type()) {
+		return false
 	}
 
-	return nil
+	return m.detectMappingType(from.Type(), to.Type()) == sameTypes
+}
+
+// copyAllowed reports whether options of the call allow copying from into to as a whole.
+func copyAllowed(st *mapState, from, to reflect.Value) bool {
+	return len(st.sels) == 0 && !st.opts.protobuf && to.IsZero() && !cachedFields(to.Type()).fieldwise &&
+		!(st.opts.normalizesTime() && containsTime(from.Type())) && !(st.opts.copyInterfaces && hasInterfaceFields(from.Type())) &&
+		st.opts.specialFloats == specialFloatsPass && st.changes == nil && len(st.opts.converters) == 0
 }
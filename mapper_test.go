@@ -1,7 +1,20 @@
 package automapper_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -271,3 +284,3999 @@ func TestMapper_Map_Converter_WithError(t *testing.T) {
 
 	assert.ErrorIs(t, err, automapper.ErrConverter)
 }
+
+type convertError struct {
+	input string
+}
+
+func (e convertError) Error() string {
+	return "can't convert " + e.input
+}
+
+func TestMapper_Map_Converter_StructError(t *testing.T) {
+	t.Parallel()
+	from := Converters2{"string"}
+	to := Converters1{}
+	m := automapper.New()
+	err := m.Set(func(s string) (int, convertError) {
+		return 0, convertError{input: s}
+	})
+	assert.NoError(t, err)
+
+	err = m.Map(&from, &to)
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.ErrorAs(t, err, new(convertError))
+
+	err = m.Compose(func(s string) (int, convertError) {
+		return 0, convertError{input: s}
+	}, func(i int) time.Duration { return time.Duration(i) })
+	assert.NoError(t, err)
+
+	err = m.Map(&from, &Converters3{})
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.ErrorAs(t, err, new(convertError))
+}
+
+type Converters3 struct {
+	Field1 time.Duration
+}
+
+func TestMapper_Compose(t *testing.T) {
+	t.Parallel()
+	from := Converters1{90}
+	to := Converters3{}
+	m := automapper.New()
+	err := m.Compose(strconv.Itoa, func(s string) (time.Duration, error) {
+		return time.ParseDuration(s + "s")
+	})
+	assert.NoError(t, err)
+
+	err = m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, to.Field1)
+}
+
+func TestMapper_Compose_Error(t *testing.T) {
+	t.Parallel()
+	from := Converters2{"a"}
+	to := Converters1{}
+	m := automapper.New()
+	err := m.Compose(strconv.Atoi, func(i int) int { return i * 2 })
+	assert.NoError(t, err)
+
+	err = m.Map(&from, &to)
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+func TestMapper_Compose_TypesMismatch(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.Compose(strconv.Itoa, strconv.Itoa)
+
+	assert.ErrorIs(t, err, automapper.ErrComposition)
+}
+
+func TestMapper_SetFallback(t *testing.T) {
+	t.Parallel()
+	from := Converters1{1}
+	to := Converters2{}
+	m := automapper.New()
+	m.SetFallback(func(from, to reflect.Value) (bool, error) {
+		if from.Kind() != reflect.Int || to.Kind() != reflect.String {
+			return false, nil
+		}
+
+		to.SetString(strconv.Itoa(int(from.Int())))
+		return true, nil
+	})
+
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", to.Field1)
+
+	err = m.Map(&Converters2{"1"}, &Converters1{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+func TestMapper_SetFallback_Error(t *testing.T) {
+	t.Parallel()
+	errFallback := errors.New("fallback error")
+	m := automapper.New()
+	m.SetFallback(func(from, to reflect.Value) (bool, error) {
+		return false, errFallback
+	})
+
+	err := m.Map(&Converters1{1}, &Converters2{})
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.ErrorIs(t, err, errFallback)
+}
+
+type Money struct {
+	Cents int
+}
+
+func (m Money) MapTo(dst interface{}) error {
+	switch dst := dst.(type) {
+	case *string:
+		*dst = strconv.Itoa(m.Cents/100) + "." + strconv.Itoa(m.Cents%100)
+	case *Money:
+		*dst = Money{Cents: m.Cents * 2}
+	default:
+		return errors.New("unsupported destination")
+	}
+
+	return nil
+}
+
+type Celsius float64
+
+func (c *Celsius) MapFrom(src interface{}) error {
+	fahrenheit, ok := src.(float64)
+	if !ok {
+		return errors.New("unsupported source")
+	}
+
+	*c = Celsius((fahrenheit - 32) * 5 / 9)
+	return nil
+}
+
+type SelfMapping1 struct {
+	Price       Money
+	Total       Money
+	Temperature float64
+}
+
+type SelfMapping2 struct {
+	Price       string
+	Total       Money
+	Temperature Celsius
+}
+
+func TestMapper_Map_SelfMapping(t *testing.T) {
+	t.Parallel()
+	from := SelfMapping1{Price: Money{Cents: 1050}, Total: Money{Cents: 1}, Temperature: 212}
+	to := SelfMapping2{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SelfMapping2{Price: "10.50", Total: Money{Cents: 2}, Temperature: 100}, to)
+}
+
+func TestMapper_Map_SelfMapping_Error(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.Map(&struct{ Price Money }{Money{1}}, &struct{ Price int }{})
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+func TestMapper_RegisterStrategy(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := m.RegisterStrategy("intToString", automapper.AfterBuiltins, func(fromType, toType reflect.Type) bool {
+		return fromType.Kind() == reflect.Int && toType.Kind() == reflect.String
+	}, func(from, to reflect.Value) error {
+		to.SetString(strconv.FormatInt(from.Int(), 10))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	to := Converters2{}
+	err = m.Map(&Converters1{1}, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", to.Field1)
+}
+
+func TestMapper_RegisterStrategy_BeforeBuiltins(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	err := m.RegisterStrategy("hex", automapper.BeforeBuiltins, func(fromType, toType reflect.Type) bool {
+		return fromType.Kind() == reflect.Int && toType.Kind() == reflect.String
+	}, func(from, to reflect.Value) error {
+		to.SetString(strconv.FormatInt(from.Int(), 16))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	to := Converters2{}
+	err = m.Map(&Converters1{255}, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ff", to.Field1)
+}
+
+func TestMapper_RegisterStrategy_Duplicate(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.RegisterStrategy("structs", automapper.AfterBuiltins, nil, nil)
+
+	assert.ErrorIs(t, err, automapper.ErrDuplicateStrategy)
+}
+
+func TestMapper_SetPrecedence(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strings.ToUpper))
+	from := Simple1{String: "value"}
+
+	to := Simple2{}
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUE", to.String)
+
+	err = m.SetPrecedence(automapper.StrategySameTypes)
+	assert.NoError(t, err)
+	to = Simple2{}
+	err = m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "value", to.String)
+}
+
+func TestMapper_SetPrecedence_Unknown(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.SetPrecedence("unknown")
+
+	assert.ErrorIs(t, err, automapper.ErrUnknownStrategy)
+}
+
+type Numeric1 struct {
+	Count int64
+	Ratio float64
+	Items []Numeric1Item
+}
+
+type Numeric1Item struct {
+	Value int
+}
+
+type Numeric2 struct {
+	Count int32
+	Ratio int
+	Items []Numeric2Item
+}
+
+type Numeric2Item struct {
+	Value uint8
+}
+
+func TestMapper_Map_NumericConversion(t *testing.T) {
+	t.Parallel()
+	from := Numeric1{Count: 1<<32 + 1, Ratio: 1.5, Items: []Numeric1Item{{Value: 256}}}
+	to := Numeric2{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to, automapper.WithNumericConversion())
+
+	assert.NoError(t, err)
+	assert.Equal(t, Numeric2{Count: 1, Ratio: 1, Items: []Numeric2Item{{Value: 0}}}, to)
+}
+
+func TestMapper_Map_StrictNumeric(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithStrictNumeric())
+	to := Numeric2{}
+
+	err := m.Map(&Numeric1{Count: 1, Ratio: 2, Items: []Numeric1Item{{Value: 255}}}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, Numeric2{Count: 1, Ratio: 2, Items: []Numeric2Item{{Value: 255}}}, to)
+
+	err = m.Map(&Numeric1{Ratio: 1.5}, &Numeric2{})
+	var numErr *automapper.NumericError
+	assert.ErrorAs(t, err, &numErr)
+	assert.Equal(t, "Ratio", numErr.Path)
+
+	err = m.Map(&Numeric1{Items: []Numeric1Item{{Value: 1}, {Value: -1}}}, &Numeric2{})
+	assert.ErrorIs(t, err, automapper.ErrLossyConversion)
+	assert.ErrorAs(t, err, &numErr)
+	assert.Equal(t, "Items[1].Value", numErr.Path)
+}
+
+type NumericCollections1 struct {
+	Counts []int64
+	Limits map[string]int64
+	Max    *int64
+	Min    int64
+	Empty  *int64
+}
+
+type NumericCollections2 struct {
+	Counts []int32
+	Limits map[string]int32
+	Max    int32
+	Min    *int32
+	Empty  int32
+}
+
+func TestMapper_Map_NumericConversion_Collections(t *testing.T) {
+	t.Parallel()
+	maxVal, minVal := int64(9), int32(1)
+	from := NumericCollections1{Counts: []int64{1, 2}, Limits: map[string]int64{"a": 3}, Max: &maxVal, Min: 1}
+	to := NumericCollections2{Empty: 7}
+	m := automapper.New()
+
+	err := m.Map(&from, &to, automapper.WithNumericConversion())
+
+	assert.NoError(t, err)
+	assert.Equal(t, NumericCollections2{
+		Counts: []int32{1, 2},
+		Limits: map[string]int32{"a": 3},
+		Max:    9,
+		Min:    &minVal,
+		Empty:  7,
+	}, to)
+
+	err = m.Map(&NumericCollections1{Counts: []int64{1, 1 << 40}}, &NumericCollections2{}, automapper.WithStrictNumeric())
+	var numErr *automapper.NumericError
+	assert.ErrorAs(t, err, &numErr)
+	assert.Equal(t, "Counts[1]", numErr.Path)
+
+	err = m.Map(&from, &NumericCollections2{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type Times struct {
+	Time    time.Time
+	TimePtr *time.Time
+	Nested  Simple1
+}
+
+func TestMapper_Map_WithTimeLocation(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	tm := time.Date(2021, 1, 1, 12, 0, 0, 0, loc)
+	from := Times{Time: tm, TimePtr: &tm, Nested: Simple1{Int: 1, Time: tm}}
+	to := Times{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to, automapper.WithTimeLocation(time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, to.Time.Location())
+	assert.Equal(t, time.UTC, to.TimePtr.Location())
+	assert.Equal(t, time.UTC, to.Nested.Time.Location())
+	assert.True(t, tm.Equal(to.Nested.Time))
+	assert.Equal(t, 1, to.Nested.Int)
+	assert.Equal(t, loc, from.TimePtr.Location())
+
+	structs := Structs2{}
+	err = m.Map(&Structs1{Field1: Simple1{Time: tm}}, &structs, automapper.WithTimeLocation(time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, structs.Field1.Time.Location())
+}
+
+type TimeCollections struct {
+	Slots    []time.Time
+	Window   [2]time.Time
+	Deadline map[string]time.Time
+	Events   []Simple1
+}
+
+func TestMapper_Map_WithTimeLocation_Collections(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	tm := time.Date(2021, 1, 1, 12, 0, 0, 0, loc)
+	from := TimeCollections{
+		Slots:    []time.Time{tm},
+		Window:   [2]time.Time{tm, tm},
+		Deadline: map[string]time.Time{"a": tm},
+		Events:   []Simple1{{Time: tm}},
+	}
+	to := TimeCollections{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to, automapper.WithTimeLocation(time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, to.Slots[0].Location())
+	assert.Equal(t, time.UTC, to.Window[1].Location())
+	assert.Equal(t, time.UTC, to.Deadline["a"].Location())
+	assert.Equal(t, time.UTC, to.Events[0].Time.Location())
+	assert.True(t, tm.Equal(to.Slots[0]))
+	assert.Equal(t, loc, from.Slots[0].Location())
+	assert.Equal(t, loc, from.Events[0].Time.Location())
+}
+
+func TestMapper_Map_WithoutMonotonic(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	from := Times{Time: now, TimePtr: &now}
+	to := Times{}
+	m := automapper.New(automapper.WithoutMonotonic())
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, now.Round(0), to.Time)
+	assert.Equal(t, now.Round(0), *to.TimePtr)
+	assert.NotEqual(t, now, to.Time)
+}
+
+func TestMapper_Map_WithoutMonotonic_Collections(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	from := TimeCollections{Slots: []time.Time{now}, Window: [2]time.Time{now}, Deadline: map[string]time.Time{"a": now}}
+	to := TimeCollections{}
+	m := automapper.New(automapper.WithoutMonotonic())
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, TimeCollections{
+		Slots:    []time.Time{now.Round(0)},
+		Window:   [2]time.Time{now.Round(0)},
+		Deadline: map[string]time.Time{"a": now.Round(0)},
+	}, to)
+	assert.NotEqual(t, now, to.Slots[0])
+}
+
+type Collections1 struct {
+	Slice    *[]Simple1
+	Map      map[string]Simple1
+	MapPtr   *map[string]*Simple1
+	SlicePtr []Simple1
+}
+
+type Collections2 struct {
+	Slice    *[]Simple2
+	Map      map[string]Simple2
+	MapPtr   *map[string]Simple2
+	SlicePtr *[]*Simple2
+}
+
+func TestMapper_Map_PointersToCollections(t *testing.T) {
+	t.Parallel()
+	from := Collections1{
+		Slice:    &[]Simple1{{Int: 1}},
+		Map:      map[string]Simple1{"a": {Int: 2}},
+		MapPtr:   &map[string]*Simple1{"b": {Int: 3}},
+		SlicePtr: []Simple1{{Int: 4}},
+	}
+	to := Collections2{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Simple2{{Int: 1}}, *to.Slice)
+	assert.Equal(t, map[string]Simple2{"a": {Int: 2}}, to.Map)
+	assert.Equal(t, map[string]Simple2{"b": {Int: 3}}, *to.MapPtr)
+	assert.Equal(t, []*Simple2{{Int: 4}}, *to.SlicePtr)
+}
+
+func TestMapper_Map_PointersToCollections_Nil(t *testing.T) {
+	t.Parallel()
+	to := Collections2{}
+	m := automapper.New()
+
+	err := m.Map(&Collections1{}, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Collections2{}, to)
+}
+
+type DoublePointers1 struct {
+	Struct **Simple1
+	Int    ***int
+	Nil    **Simple1
+}
+
+type DoublePointers2 struct {
+	Struct **Simple2
+	Int    **int
+	Nil    **Simple2
+}
+
+func TestMapper_Map_DoublePointers(t *testing.T) {
+	t.Parallel()
+	simple := &Simple1{Int: 1}
+	i := 2
+	iPtr := &i
+	iPtrPtr := &iPtr
+	var nilSimple *Simple1
+	from := DoublePointers1{Struct: &simple, Int: &iPtrPtr, Nil: &nilSimple}
+	to := DoublePointers2{}
+	m := automapper.New()
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, (**to.Struct).Int)
+	assert.Equal(t, 2, **to.Int)
+	assert.Nil(t, to.Nil)
+}
+
+func TestMapper_Map_DoublePointers_Params(t *testing.T) {
+	t.Parallel()
+	from := &Simple1{Int: 1}
+	var to *Simple2
+	m := automapper.New()
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Simple2{Int: 1}, to)
+}
+
+type NestedSlices1 struct {
+	Matrix [][]*Simple1
+	Grid   [2][]Simple1
+	Ints   []int
+}
+
+type NestedSlices2 struct {
+	Matrix [][]Simple2
+	Grid   [2][]*Simple2
+	Ints   []string
+}
+
+func TestMapper_Map_NestedSlices(t *testing.T) {
+	t.Parallel()
+	from := NestedSlices1{
+		Matrix: [][]*Simple1{{{Int: 1}, {Int: 2}}, {{Int: 3}}},
+		Grid:   [2][]Simple1{{{Int: 4}}, nil},
+		Ints:   []int{5, 6},
+	}
+	to := NestedSlices2{}
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]Simple2{{{Int: 1}, {Int: 2}}, {{Int: 3}}}, to.Matrix)
+	assert.Equal(t, [2][]*Simple2{{{Int: 4}}, nil}, to.Grid)
+	assert.Equal(t, []string{"5", "6"}, to.Ints)
+}
+
+type Node1 struct {
+	Value    int
+	Next     *Node1
+	Children []*Node1
+}
+
+type Node2 struct {
+	Value    int
+	Next     *Node2
+	Children []*Node2
+}
+
+func TestMapper_Map_SelfReferential(t *testing.T) {
+	t.Parallel()
+	leaf := &Node1{Value: 3}
+	from := &Node1{Value: 1, Next: &Node1{Value: 2, Next: leaf}, Children: []*Node1{leaf, {Value: 4}}}
+	to := Node2{}
+	m := automapper.New()
+
+	err := m.Map(from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, to.Next.Next.Value)
+	assert.Equal(t, 4, to.Children[1].Value)
+	// shared nodes stay shared
+	assert.Same(t, to.Next.Next, to.Children[0])
+}
+
+func TestMapper_Map_SelfReferential_Cycle(t *testing.T) {
+	t.Parallel()
+	first := &Node1{Value: 1}
+	first.Next = &Node1{Value: 2, Next: first}
+	to := &Node2{}
+	m := automapper.New()
+
+	err := m.Map(&Node1{Next: first}, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, to.Next.Next.Value)
+	assert.Same(t, to.Next, to.Next.Next.Next)
+}
+
+func TestMapper_Map_SelfReferential_RootCycle(t *testing.T) {
+	t.Parallel()
+	first := &Node1{Value: 1}
+	first.Next = &Node1{Value: 2, Next: first}
+	to := &Node2{}
+	m := automapper.New()
+
+	err := m.Map(first, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, to.Next.Value)
+	assert.Same(t, to, to.Next.Next)
+}
+
+func TestMapper_Map_WithMaxDepth(t *testing.T) {
+	t.Parallel()
+	from := Node1{Value: 1, Next: &Node1{Value: 2, Next: &Node1{Value: 3, Children: []*Node1{{Value: 4}}}}}
+	m := automapper.New()
+
+	err := m.Map(&from, &Node2{}, automapper.WithMaxDepth(3))
+	var depthErr *automapper.DepthError
+	assert.ErrorIs(t, err, automapper.ErrMaxDepth)
+	assert.ErrorAs(t, err, &depthErr)
+	assert.Equal(t, "Next.Next.Children[0]", depthErr.Path)
+
+	to := Node2{}
+	err = m.Map(&from, &to, automapper.WithMaxDepth(4))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, to.Next.Next.Children[0].Value)
+}
+
+func TestMapper_CreateMap(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.CreateMap(Structs1{}, &Structs2{})
+	assert.NoError(t, err)
+
+	err = m.CreateMap(Slices1{}, 1)
+	assert.ErrorIs(t, err, automapper.ErrNotAStruct)
+}
+
+func TestMapper_CreateMap_NestedMissingConverter(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	err := m.CreateMap(struct{ Nested []Converters1 }{}, struct{ Nested []Converters2 }{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+	assert.Contains(t, err.Error(), "Converters2.Field1")
+
+	assert.NoError(t, m.Set(strconv.Itoa))
+	err = m.CreateMap(struct{ Nested []Converters1 }{}, struct{ Nested []Converters2 }{})
+	assert.NoError(t, err)
+}
+
+type Container1 struct {
+	Payload Simple1
+}
+
+type Container2 struct {
+	Payload interface{}
+}
+
+func TestMapper_Map_InterfaceDestination(t *testing.T) {
+	t.Parallel()
+	payload := &Simple2{String: "keep"}
+	to := Container2{Payload: payload}
+	m := automapper.New()
+
+	err := m.Map(&Container1{Payload: Simple1{Int: 1}}, &to)
+
+	assert.NoError(t, err)
+	assert.Same(t, payload, to.Payload)
+	assert.Equal(t, Simple2{Int: 1, String: "keep"}, *payload)
+
+	err = m.Map(&Container1{Payload: Simple1{Int: 1}}, &Container2{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+func TestMapper_Map_InvalidArguments(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var nilPtr *Simple1
+
+	assert.ErrorIs(t, m.Map(&Simple1{}, Simple2{}), automapper.ErrNotAPtr)
+	assert.ErrorIs(t, m.Map(Simple1{}, &Simple2{}), automapper.ErrNotAPtr)
+	assert.ErrorIs(t, m.Map(nil, &Simple2{}), automapper.ErrNilValue)
+	assert.ErrorIs(t, m.Map(nilPtr, &Simple2{}), automapper.ErrNilValue)
+	assert.ErrorIs(t, m.Map(&Simple1{}, (*Simple2)(nil)), automapper.ErrNilValue)
+	assert.ErrorIs(t, m.Map(&nilPtr, &Simple2{}), automapper.ErrNilValue)
+	assert.ErrorIs(t, m.Map(&nilPtr, &Simple2{}), automapper.ErrNilSource)
+	assert.ErrorIs(t, m.Map(&Simple1{}, &[]Simple2{}), automapper.ErrUnsupportedMapping)
+}
+
+type Payload interface {
+	Kind() string
+}
+
+type TextPayload struct {
+	Text string
+}
+
+func (p *TextPayload) Kind() string { return "text" }
+
+type NumberPayload struct {
+	Int   int
+	Float float64
+}
+
+func (p *NumberPayload) Kind() string { return "number" }
+
+type Text struct {
+	Text string
+}
+
+type Text2 struct {
+	Message string
+}
+
+func TestMapper_Map_InterfaceSlices(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	m.RegisterImplementations(&TextPayload{}, &NumberPayload{})
+
+	payloads := struct{ Items []Payload }{}
+	err := m.Map(&struct{ Items []Simple1 }{[]Simple1{{Int: 1, Float64: 2}}}, &payloads)
+	assert.NoError(t, err)
+	assert.Equal(t, []Payload{&NumberPayload{Int: 1}}, payloads.Items)
+
+	payloads = struct{ Items []Payload }{}
+	err = m.Map(&struct{ Items []interface{} }{[]interface{}{Text{"a"}, &Simple1{Int: 2}}}, &payloads)
+	assert.NoError(t, err)
+	assert.Equal(t, []Payload{&TextPayload{Text: "a"}, &NumberPayload{Int: 2}}, payloads.Items)
+
+	anys := struct{ Items []interface{} }{}
+	err = m.Map(&struct{ Items []Text }{[]Text{{"b"}}}, &anys)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{&TextPayload{Text: "b"}}, anys.Items)
+
+	err = m.Map(&struct{ Items []Text2 }{[]Text2{{"c"}}}, &payloads)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type Envelope struct {
+	Payload Payload
+	Meta    interface{}
+}
+
+func TestMapper_Map_InterfaceCopies(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(func(tags []string) []string { return append([]string(nil), tags...) }))
+
+	from := Envelope{Payload: &TextPayload{Text: "a"}, Meta: []string{"b"}}
+	shared := Envelope{}
+	err := m.Map(&from, &shared)
+	assert.NoError(t, err)
+	assert.Same(t, from.Payload, shared.Payload)
+
+	copied := Envelope{}
+	err = m.Map(&from, &copied, automapper.WithInterfaceCopies())
+	assert.NoError(t, err)
+	assert.Equal(t, from, copied)
+	assert.NotSame(t, from.Payload, copied.Payload)
+	copied.Meta.([]string)[0] = "c"
+	assert.Equal(t, []string{"b"}, from.Meta)
+
+	// nil sources are skipped
+	err = m.Map(&Envelope{}, &copied, automapper.WithInterfaceCopies())
+	assert.NoError(t, err)
+	assert.Equal(t, &TextPayload{Text: "a"}, copied.Payload)
+}
+
+type TagSet struct {
+	tags map[string]bool
+}
+
+func NewTagSet(tags ...string) TagSet {
+	set := TagSet{tags: make(map[string]bool)}
+	for _, tag := range tags {
+		set.tags[tag] = true
+	}
+
+	return set
+}
+
+func (s TagSet) Clone() TagSet {
+	return NewTagSet(s.Tags()...)
+}
+
+func (s TagSet) Copy() TagSet {
+	return NewTagSet(append(s.Tags(), "copied")...)
+}
+
+func (s TagSet) Tags() []string {
+	tags := make([]string, 0, len(s.tags))
+	for tag := range s.tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+type Article struct {
+	Tags   TagSet
+	Author struct{ Tags TagSet }
+}
+
+func TestMapper_SetCloneMethod(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	from := Article{Tags: NewTagSet("a")}
+	from.Author.Tags = NewTagSet("b")
+	to := Article{}
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	from.Tags.tags["c"] = true
+	from.Author.Tags.tags["c"] = true
+	assert.Equal(t, []string{"a"}, to.Tags.Tags())
+	assert.Equal(t, []string{"b"}, to.Author.Tags.Tags())
+
+	m.SetCloneMethod("Copy")
+	err = m.Map(&Article{Tags: NewTagSet("a")}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "copied"}, to.Tags.Tags())
+
+	m.SetCloneMethod("")
+	err = m.Map(&from, &to)
+	assert.NoError(t, err)
+	from.Tags.tags["d"] = true
+	assert.True(t, to.Tags.tags["d"])
+}
+
+type Counters struct {
+	Hits    atomic.Int64
+	Enabled atomic.Bool
+	Misses  *atomic.Uint32
+}
+
+type CountersDTO struct {
+	Hits    int64
+	Enabled bool
+	Misses  uint32
+}
+
+func TestMapper_Map_Atomics(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	counters := &Counters{Misses: &atomic.Uint32{}}
+	counters.Hits.Store(3)
+	counters.Enabled.Store(true)
+	counters.Misses.Store(2)
+	dto := CountersDTO{}
+	err := m.Map(counters, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, CountersDTO{Hits: 3, Enabled: true, Misses: 2}, dto)
+
+	mapped := &Counters{}
+	err = m.Map(&CountersDTO{Hits: 4, Misses: 1}, mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), mapped.Hits.Load())
+	assert.False(t, mapped.Enabled.Load())
+	assert.Equal(t, uint32(1), mapped.Misses.Load())
+
+	err = m.Map(&struct{ Hits string }{"1"}, mapped)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type AccountRow struct {
+	ID       int
+	Username string
+	Email    string
+}
+
+type AccountDTO struct {
+	IDDto    int
+	UserName string
+	Email    string `mapper:"EmailDto"`
+}
+
+func TestMapper_Map_NameTransformers(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(
+		automapper.WithSourceNameTransformer(strings.ToLower),
+		automapper.WithDestNameTransformer(func(name string) string { return strings.TrimSuffix(name, "Dto") }),
+		automapper.WithDestNameTransformer(strings.ToLower),
+	)
+
+	dto := AccountDTO{}
+	err := m.Map(&AccountRow{ID: 1, Username: "user", Email: "email"}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountDTO{IDDto: 1, UserName: "user", Email: "email"}, dto)
+
+	// destination names are used in paths
+	err = automapper.New(automapper.WithDestNameTransformer(strings.ToLower), automapper.WithSourceNameTransformer(strings.ToLower)).
+		Map(&struct{ Id string }{"1"}, &struct{ ID int }{})
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "ID", mapErr.Path)
+}
+
+func TestMapper_Map_NewOnlyOptions(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithSourceNameTransformer(strings.ToLower))
+	for _, opt := range []automapper.Option{
+		automapper.WithSourceNameTransformer(strings.ToLower),
+		automapper.StripDestSuffix("DTO"),
+		automapper.WithPlanCacheSize(1),
+	} {
+		err := m.Map(&AccountRow{ID: 1}, &AccountDTO{}, opt)
+		assert.ErrorIs(t, err, automapper.ErrInvalidOption)
+		var mapErr *automapper.Error
+		if assert.True(t, errors.As(err, &mapErr)) {
+			assert.Equal(t, automapper.CodeInvalidConfig, mapErr.Code)
+		}
+
+		err = m.MapEach([]AccountRow{{ID: 1}}, func(AccountDTO) error { return nil }, opt)
+		assert.ErrorIs(t, err, automapper.ErrInvalidOption)
+
+		err = m.Patch(map[string]interface{}{"Email": "email"}, &AccountRow{}, opt)
+		assert.ErrorIs(t, err, automapper.ErrInvalidOption)
+	}
+
+	err := m.Map(&AccountRow{ID: 1}, &AccountDTO{}, automapper.WithNumericConversion())
+	assert.NoError(t, err)
+}
+
+func TestMapper_Map_NamePrefixes(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.StripSourcePrefix("Db"), automapper.StripDestSuffix("DTO"))
+
+	to := struct {
+		UserName string
+		EmailDTO string
+		DTO      string
+	}{}
+	err := m.Map(&struct{ DbUserName, Email, DTO string }{"user", "email", "dto"}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", to.UserName)
+	assert.Equal(t, "email", to.EmailDTO)
+	assert.Equal(t, "dto", to.DTO)
+
+	m = automapper.New(automapper.RecognizePrefixes("Db", "Src"))
+	to2 := struct{ Name, Email string }{}
+	err = m.Map(&struct{ DbName, SrcEmail string }{"name", "email"}, &to2)
+	assert.NoError(t, err)
+	assert.Equal(t, "name", to2.Name)
+	assert.Equal(t, "email", to2.Email)
+}
+
+type Attributes struct {
+	ID    int
+	Color string `mapper:"color"`
+	Size  int
+	Extra map[string]interface{} `mapper:",remain"`
+}
+
+func TestMapper_Map_Remain(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	to := struct {
+		ID    int
+		Attrs map[string]interface{} `mapper:",remain"`
+	}{}
+	err := m.Map(&Attributes{ID: 1, Color: "red", Extra: map[string]interface{}{"a": 1}}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, to.ID)
+	// zero fields are skipped, entries of source remain map are merged
+	assert.Equal(t, map[string]interface{}{"color": "red", "a": 1}, to.Attrs)
+
+	sizes := struct {
+		Sizes map[string]string `mapper:",remain"`
+	}{}
+	assert.NoError(t, m.Set(strconv.Itoa))
+	err = m.Map(&Attributes{Color: "red", Size: 2}, &sizes)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"color": "red", "Size": "2"}, sizes.Sizes)
+}
+
+func TestMapper_Map_RemainSource(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Atoi))
+
+	type attributes struct {
+		ID    int
+		Attrs map[string]interface{} `mapper:",remain"`
+	}
+
+	from := attributes{ID: 1, Attrs: map[string]interface{}{"color": "red", "Size": "2", "weight": 3}}
+	to := Attributes{}
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	// entries without destination fields are merged into destination remain map
+	assert.Equal(t, Attributes{ID: 1, Color: "red", Size: 2, Extra: map[string]interface{}{"weight": 3}}, to)
+
+	back := attributes{}
+	err = m.Map(&to, &back)
+	assert.NoError(t, err)
+	assert.Equal(t, attributes{ID: 1, Attrs: map[string]interface{}{"color": "red", "Size": 2, "weight": 3}}, back)
+
+	err = m.Map(&attributes{Attrs: map[string]interface{}{"Size": "big"}}, &to)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+type Settings struct {
+	Theme  string
+	Labels map[string]string
+}
+
+type Profile struct {
+	Settings  *Settings
+	Settings2 []Settings
+	ByName    map[string]*Settings
+}
+
+func TestMapper_SetFactory(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.ErrorIs(t, m.SetFactory(func() Settings { return Settings{} }), automapper.ErrNotAFactory)
+	assert.NoError(t, m.SetFactory(func() *Settings {
+		return &Settings{Theme: "light", Labels: map[string]string{}}
+	}))
+
+	type settings struct{ Theme string }
+	from := struct {
+		Settings  *settings
+		Settings2 []settings
+		ByName    map[string]*settings
+	}{&settings{}, []settings{{"dark"}, {}}, map[string]*settings{"a": {"dark"}}}
+	to := Profile{}
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, &Settings{Theme: "light", Labels: map[string]string{}}, to.Settings)
+	assert.Equal(t, []Settings{
+		{Theme: "dark", Labels: map[string]string{}},
+		{Theme: "light", Labels: map[string]string{}},
+	}, to.Settings2)
+	assert.Equal(t, &Settings{Theme: "dark", Labels: map[string]string{}}, to.ByName["a"])
+}
+
+type Tenant struct {
+	Name string
+}
+
+type Order struct {
+	Tenant  string
+	Company *Company
+	Items   []*OrderItem
+}
+
+type OrderItem struct {
+	Order *Order
+	Name  string
+}
+
+type Company struct {
+	Name string
+}
+
+func TestMapper_SetAllocationHook(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var paths []string
+	m.SetAllocationHook(func(ptr, parent interface{}, path string) {
+		paths = append(paths, path)
+		if item, ok := ptr.(*OrderItem); ok {
+			item.Order = parent.(*Order)
+		}
+	})
+
+	type item struct{ Name string }
+	from := struct {
+		Company *Tenant
+		Items   []item
+	}{&Tenant{Name: "c"}, []item{{"a"}, {"b"}}}
+	to := Order{}
+	err := m.Map(&from, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Company", "Items[0]", "Items[1]"}, paths)
+	assert.Equal(t, &Company{Name: "c"}, to.Company)
+	assert.Same(t, &to, to.Items[1].Order)
+	assert.Equal(t, "b", to.Items[1].Name)
+}
+
+func TestMapper_Map_StrictNil(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	type company struct{ Name string }
+	type customer struct {
+		Address  *company
+		Previous []*company
+	}
+
+	to := struct {
+		Address  Company
+		Previous []Company
+	}{}
+	err := m.Map(&customer{}, &to)
+	assert.NoError(t, err)
+
+	err = m.Map(&customer{}, &to, automapper.WithStrictNil())
+	var nilErr *automapper.NilSourceError
+	assert.True(t, errors.As(err, &nilErr))
+	assert.Equal(t, "Address", nilErr.Path)
+
+	err = m.Map(&customer{Address: &company{}, Previous: []*company{{}, nil}}, &to, automapper.WithStrictNil())
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeNilSource, mapErr.Code)
+	assert.Equal(t, "Previous[1]", mapErr.Path)
+
+	// nil pointers are mapped into pointers
+	err = m.Map(&customer{Address: &company{}}, &customer{}, automapper.WithStrictNil())
+	assert.NoError(t, err)
+}
+
+func TestMapper_Set_Concurrent(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	type ids struct{ ID int }
+	type strIDs struct{ ID string }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, m.Set(strconv.Itoa))
+			assert.NoError(t, m.Set(strconv.FormatBool))
+		}()
+		go func() {
+			defer wg.Done()
+			_ = m.Map(&ids{ID: 1}, &strIDs{})
+		}()
+	}
+
+	wg.Wait()
+	to := strIDs{}
+	assert.NoError(t, m.Map(&ids{ID: 1}, &to))
+	assert.Equal(t, "1", to.ID)
+}
+
+func TestMapper_Snapshot(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	type item struct{ ID int }
+	type order struct{ Items []item }
+	type itemDTO struct{ ID string }
+	type orderDTO struct{ Items []itemDTO }
+	assert.NoError(t, m.Map(&order{}, &orderDTO{}))
+
+	snap := m.Snapshot()
+	// changes of the mapper don't affect snapshot
+	assert.NoError(t, m.Set(func(int) string { return "changed" }))
+	m.RegisterImplementations(&TextPayload{})
+
+	to := orderDTO{}
+	err := snap.Map(&order{Items: []item{{ID: 1}}}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, orderDTO{Items: []itemDTO{{ID: "1"}}}, to)
+
+	// pairs unknown to snapshot are planned on use
+	str := struct{ ID string }{}
+	err = snap.Map(&item{ID: 2}, &str)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", str.ID)
+
+	err = m.Map(&item{ID: 2}, &str)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", str.ID)
+	// snapshot doesn't count stats of the mapper
+	assert.Equal(t, uint64(1), m.Stats().ConverterCalls)
+}
+
+func TestAdapter(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	type item struct{ ID int }
+	type itemDTO struct{ ID string }
+
+	var items automapper.TypedMapper[item, itemDTO] = automapper.NewAdapter[item, itemDTO](m)
+	dto, err := items.Map(item{ID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, itemDTO{ID: "1"}, dto)
+
+	dtos, err := items.MapSlice([]item{{ID: 1}, {ID: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []itemDTO{{ID: "1"}, {ID: "2"}}, dtos)
+
+	dtos, err = items.MapSlice(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, dtos)
+
+	dto = itemDTO{ID: "keep"}
+	err = items.MapInto(&item{}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, "keep", dto.ID)
+
+	ptrs := automapper.NewAdapter[*item, itemDTO](m)
+	_, err = ptrs.Map(nil)
+	assert.ErrorIs(t, err, automapper.ErrNilSource)
+}
+
+type RawConfig struct {
+	Port    interface{}
+	Debug   string
+	Verbose int
+	Hosts   string
+	Weights []string
+	Ratio   float64
+	Enabled bool
+}
+
+type Config struct {
+	Port    int
+	Debug   bool
+	Verbose bool
+	Hosts   []string
+	Weights []int
+	Ratio   string
+	Enabled string
+}
+
+func TestMapper_Map_WeaklyTypedInput(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	from := RawConfig{Port: "8080", Debug: "true", Verbose: 2, Hosts: "a", Weights: []string{"1", "2"}, Ratio: 0.5, Enabled: true}
+	err := m.Map(&from, &Config{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+
+	config := Config{}
+	err = m.Map(&from, &config, automapper.WithWeaklyTypedInput())
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Port: 8080, Debug: true, Verbose: true, Hosts: []string{"a"}, Weights: []int{1, 2}, Ratio: "0.5", Enabled: "1",
+	}, config)
+
+	err = m.Map(&RawConfig{Debug: "yes"}, &config, automapper.WithWeaklyTypedInput())
+	var convErr *automapper.ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Debug", convErr.Path)
+}
+
+type Reading struct {
+	Value   float64
+	Samples []float64
+	Ratio   float64
+}
+
+type ReadingDTO struct {
+	Value   float64
+	Samples []float64
+	Ratio   *float64
+}
+
+func TestMapper_Map_SpecialFloats(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	from := Reading{Value: math.NaN(), Samples: []float64{1, math.Inf(1)}, Ratio: math.Inf(-1)}
+	reading := ReadingDTO{}
+	err := m.Map(&from, &reading)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(reading.Value))
+	assert.True(t, math.IsInf(reading.Samples[1], 1))
+
+	reading = ReadingDTO{}
+	err = m.Map(&from, &reading, automapper.WithSpecialFloatSubstitute(0))
+	assert.NoError(t, err)
+	assert.Equal(t, ReadingDTO{Samples: []float64{1, 0}, Ratio: new(float64)}, reading)
+
+	err = m.Map(&Reading{Samples: []float64{math.NaN()}}, &ReadingDTO{}, automapper.WithSpecialFloatCheck())
+	var floatErr *automapper.SpecialFloatError
+	assert.True(t, errors.As(err, &floatErr))
+	assert.Equal(t, "Samples[0]", floatErr.Path)
+	assert.True(t, math.IsNaN(floatErr.Value))
+
+	err = m.Map(&from, &Reading{}, automapper.WithSpecialFloatCheck())
+	assert.ErrorIs(t, err, automapper.ErrSpecialFloat)
+}
+
+type TrackedEntity struct {
+	Name  string
+	Tags  []string
+	Owner *string
+}
+
+func TestMapper_Map_SkipEqualWrites(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	owner := "alice"
+	entity := TrackedEntity{Name: "a", Tags: []string{"x", "y"}, Owner: &owner}
+	tags, ownerPtr := &entity.Tags[0], entity.Owner
+
+	newOwner := "alice"
+	from := TrackedEntity{Name: "b", Tags: []string{"x", "y"}, Owner: &newOwner}
+	err := m.Map(&from, &entity, automapper.WithSkipEqualWrites())
+	assert.NoError(t, err)
+	assert.Equal(t, "b", entity.Name)
+	assert.Same(t, tags, &entity.Tags[0])
+	assert.Same(t, ownerPtr, entity.Owner)
+
+	from.Tags = []string{"z"}
+	err = m.Map(&from, &entity, automapper.WithSkipEqualWrites())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z"}, entity.Tags)
+
+	err = m.Map(&from, &entity)
+	assert.NoError(t, err)
+	assert.NotSame(t, ownerPtr, entity.Owner)
+}
+
+func TestMapper_MapTrack(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	order := Order{Tenant: "a", Items: []*OrderItem{{Name: "x"}}}
+	changes, err := m.MapTrack(&Order{Tenant: "a", Items: []*OrderItem{{Name: "x"}}}, &order)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, changes)
+
+	changes, err = m.MapTrack(&Order{Tenant: "b", Items: []*OrderItem{{Name: "y"}}}, &order)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tenant", "Items"}, changes)
+	assert.Equal(t, "y", order.Items[0].Name)
+}
+
+type TrackedVisit struct {
+	At   time.Time
+	Addr MaskedAddress
+}
+
+func TestMapper_MapTrack_Structs(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	visit := TrackedVisit{At: at, Addr: MaskedAddress{City: "a", Zip: "1"}}
+	changes, err := m.MapTrack(&TrackedVisit{At: at, Addr: MaskedAddress{City: "a", Zip: "1"}}, &visit)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, changes)
+
+	from := TrackedVisit{At: at.Add(time.Hour), Addr: MaskedAddress{City: "b", Zip: "2"}}
+	diff, err := m.Diff(&from, &visit)
+	assert.NoError(t, err)
+	changes, err = m.MapTrack(&from, &visit)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"At", "Addr.City", "Addr.Zip"}, changes)
+	assert.ElementsMatch(t, diff, changes)
+	assert.Equal(t, from, visit)
+
+	// equal structs aren't written with skipped equal writes
+	changes, err = m.MapTrack(&from, &visit, automapper.WithSkipEqualWrites())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, changes)
+}
+
+type Inventory struct {
+	Stock map[string]string
+}
+
+type InventoryDTO struct {
+	Stock map[string]int
+}
+
+type StockLevels struct {
+	Min int
+	Max int
+}
+
+func TestMapper_Map_DeterministicErrors(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Atoi))
+
+	from := Inventory{Stock: map[string]string{"d": "x", "b": "y", "c": "1", "e": "z"}}
+	for i := 0; i < 20; i++ {
+		err := m.Map(&from, &InventoryDTO{})
+		var mapErr *automapper.Error
+		assert.True(t, errors.As(err, &mapErr))
+		assert.Equal(t, "Stock[b]", mapErr.Path)
+
+		err = m.Map(&map[string]string{"max": "x", "min": "y"}, &StockLevels{})
+		assert.Contains(t, err.Error(), `"y"`)
+	}
+}
+
+func TestMapper_Map_DeterministicErrors_ConvertsOnce(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	calls := make(map[string]int)
+	assert.NoError(t, m.Set(func(s string) (int, error) {
+		calls[s]++
+		return strconv.Atoi(s)
+	}))
+
+	err := m.Map(&Inventory{Stock: map[string]string{"d": "x", "b": "y", "c": "1"}}, &InventoryDTO{})
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Stock[b]", mapErr.Path)
+	assert.Equal(t, map[string]int{"x": 1, "y": 1, "1": 1}, calls)
+}
+
+type Meeting struct {
+	Start time.Time
+	Slots []time.Time
+}
+
+type MeetingView struct {
+	Start string
+	Slots []string
+}
+
+func TestMapper_Map_WithConverters(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(func(t time.Time) string { return t.Format(time.RFC3339) }))
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	from := Meeting{Start: start, Slots: []time.Time{start}}
+
+	epoch := func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+	view := MeetingView{}
+	err := m.Map(&from, &view, automapper.WithConverters(epoch))
+	assert.NoError(t, err)
+	assert.Equal(t, MeetingView{Start: "1577934245", Slots: []string{"1577934245"}}, view)
+
+	view = MeetingView{}
+	err = m.Map(&from, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, MeetingView{Start: "2020-01-02T03:04:05Z", Slots: []string{"2020-01-02T03:04:05Z"}}, view)
+
+	err = m.Map(&from, &view, automapper.WithConverters("epoch"))
+	assert.ErrorIs(t, err, automapper.ErrNotAFn)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeInvalidConfig, mapErr.Code)
+}
+
+type Readings struct {
+	Values  []int
+	Pair    [2]int
+	ByName  map[string]int
+	Grouped map[string][]int
+}
+
+type ReadingsView struct {
+	Values  []string
+	Pair    [2]string
+	ByName  map[string]string
+	Grouped map[string][]string
+}
+
+func TestMapper_Map_LiftedConverters(t *testing.T) {
+	t.Parallel()
+	from := Readings{Values: []int{1, 2}, Pair: [2]int{3, 4}, ByName: map[string]int{"a": 5}, Grouped: map[string][]int{"b": {6}}}
+	want := ReadingsView{
+		Values: []string{"1", "2"}, Pair: [2]string{"3", "4"}, ByName: map[string]string{"a": "5"}, Grouped: map[string][]string{"b": {"6"}},
+	}
+
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	view := ReadingsView{}
+	err := m.Map(&from, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, want, view)
+
+	view = ReadingsView{}
+	err = automapper.New().Map(&from, &view, automapper.WithConverters(strconv.Itoa))
+	assert.NoError(t, err)
+	assert.Equal(t, want, view)
+
+	err = automapper.New().Map(&from, &ReadingsView{})
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type PointerReadings struct {
+	Refs   []*int
+	Values []int
+	ByName map[string]*int
+}
+
+type PointerReadingsView struct {
+	Refs   []string
+	Values []*string
+	ByName map[string]string
+}
+
+func TestMapper_Map_LiftedPointerConverters(t *testing.T) {
+	t.Parallel()
+	one, two := 1, 2
+	from := PointerReadings{Refs: []*int{&one, nil}, Values: []int{2}, ByName: map[string]*int{"a": &two}}
+	registered := automapper.New()
+	assert.NoError(t, registered.Set(strconv.Itoa))
+
+	for _, tc := range []struct {
+		name string
+		m    *automapper.Mapper
+		opts []automapper.Option
+	}{
+		{name: "registered", m: registered},
+		{name: "scoped", m: automapper.New(), opts: []automapper.Option{automapper.WithConverters(strconv.Itoa)}},
+	} {
+		view := PointerReadingsView{}
+		err := tc.m.Map(&from, &view, tc.opts...)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, []string{"1", ""}, view.Refs, tc.name)
+		assert.Equal(t, map[string]string{"a": "2"}, view.ByName, tc.name)
+		if assert.Len(t, view.Values, 1, tc.name) {
+			assert.Equal(t, "2", *view.Values[0], tc.name)
+		}
+	}
+}
+
+type Histogram struct {
+	Buckets map[int]int
+	Labels  map[int]string
+}
+
+type HistogramView struct {
+	Buckets map[string]string
+	Labels  map[string]string
+}
+
+func TestMapper_Map_LiftedMapConverters(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	assert.NoError(t, m.Set(strconv.Atoi))
+
+	view := HistogramView{}
+	err := m.Map(&Histogram{Buckets: map[int]int{10: 2}, Labels: map[int]string{20: "high"}}, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, HistogramView{Buckets: map[string]string{"10": "2"}, Labels: map[string]string{"20": "high"}}, view)
+
+	err = m.Map(&HistogramView{Labels: map[string]string{"": "none"}}, &Histogram{})
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Labels[]", mapErr.Path)
+}
+
+type Price struct {
+	Cents    int64
+	Currency string
+}
+
+type Listing struct {
+	Price    Price
+	Discount *Price
+	Stock    int
+	Note     int
+}
+
+type ListingView struct {
+	Price    string
+	Discount string
+	Stock    string
+	Note     *string
+}
+
+func TestMapper_Map_PointerConverters(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(func(p *Price) string { return fmt.Sprintf("%d %s", p.Cents, p.Currency) }))
+	assert.NoError(t, m.Set(func(i int) *string {
+		if i < 0 {
+			return nil
+		}
+
+		s := strconv.Itoa(i)
+		return &s
+	}))
+
+	view := ListingView{Stock: "unknown"}
+	err := m.Map(&Listing{Price: Price{Cents: 100, Currency: "EUR"}, Discount: &Price{Cents: 5, Currency: "EUR"}, Stock: -1, Note: 7}, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, "100 EUR", view.Price)
+	assert.Equal(t, "5 EUR", view.Discount)
+	assert.Equal(t, "unknown", view.Stock)
+	assert.Equal(t, "7", *view.Note)
+
+	err = m.Map(&Listing{Stock: 3}, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", view.Stock)
+}
+
+type priceFormatter struct {
+	currency string
+}
+
+func (f priceFormatter) Format(cents int64) string {
+	return fmt.Sprintf("%d %s", cents, f.currency)
+}
+
+type PriceTag struct {
+	Cents int64
+}
+
+type PriceTagView struct {
+	Cents string
+}
+
+func TestMapper_Set_Signatures(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(priceFormatter{currency: "EUR"}.Format))
+
+	view := PriceTagView{}
+	err := m.Map(&PriceTag{Cents: 250}, &view)
+	assert.NoError(t, err)
+	assert.Equal(t, PriceTagView{Cents: "250 EUR"}, view)
+
+	for _, converter := range []interface{}{
+		priceFormatter.Format,
+		func(values ...int) string { return "" },
+		func() string { return "" },
+		func(int) {},
+		func(int) (string, int, error) { return "", 0, nil },
+	} {
+		assert.ErrorIs(t, m.Set(converter), automapper.ErrBadConverterSignature)
+	}
+
+	err = m.Set(func(int) (string, int) { return "", 0 })
+	assert.ErrorIs(t, err, automapper.ErrBadConverterSignature)
+	assert.ErrorIs(t, err, automapper.ErrConverterErrorUnknownType)
+	assert.ErrorIs(t, m.Set(nil), automapper.ErrNotAFn)
+
+	err = m.Map(&PriceTag{}, &view, automapper.WithConverters(func(int64, int) string { return "" }))
+	assert.ErrorIs(t, err, automapper.ErrBadConverterSignature)
+}
+
+func TestMapper_Converters(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.Empty(t, m.Converters())
+	assert.NoError(t, m.Set(strconv.Itoa))
+	assert.NoError(t, m.Set(strconv.Atoi))
+	assert.NoError(t, m.Set(priceFormatter{currency: "EUR"}.Format))
+
+	assert.Equal(t, []automapper.ConverterInfo{
+		{From: reflect.TypeOf(0), To: reflect.TypeOf(""), Name: "strconv.Itoa"},
+		{From: reflect.TypeOf(int64(0)), To: reflect.TypeOf(""), Name: "github.com/lebedevars/automapper_test.priceFormatter.Format-fm"},
+		{From: reflect.TypeOf(""), To: reflect.TypeOf(0), Name: "strconv.Atoi"},
+	}, m.Converters())
+}
+
+type StockItem struct {
+	SKU   string
+	Price int64
+	TTL   int
+}
+
+type StockItemView struct {
+	SKU   string
+	Price string
+	TTL   time.Duration
+}
+
+func TestMapper_Mappings(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.Empty(t, m.Mappings())
+	assert.NoError(t, m.Set(priceFormatter{currency: "EUR"}.Format))
+	assert.NoError(t, m.Map(&StockItem{SKU: "a", Price: 1}, &StockItemView{}))
+
+	mappings := m.Mappings()
+	if !assert.Len(t, mappings, 1) {
+		return
+	}
+
+	assert.Equal(t, reflect.TypeOf(StockItem{}), mappings[0].From)
+	assert.Equal(t, reflect.TypeOf(StockItemView{}), mappings[0].To)
+	fields := mappings[0].Fields
+	assert.Equal(t, automapper.FieldPlan{
+		Name: "SKU", Source: "SKU", From: reflect.TypeOf(""), To: reflect.TypeOf(""), Strategy: automapper.StrategySameTypes,
+	}, fields[0])
+	assert.Equal(t, automapper.StrategyConverter, fields[1].Strategy)
+	assert.Equal(t, "github.com/lebedevars/automapper_test.priceFormatter.Format-fm", fields[1].Converter)
+	assert.ErrorIs(t, fields[2].Err, automapper.ErrMissingConverter)
+
+	mappings[0].Fields[0].Name = "changed"
+	assert.Equal(t, "SKU", m.Mappings()[0].Fields[0].Name)
+}
+
+type StockOrder struct {
+	ID    int
+	Items []StockItem
+}
+
+type StockOrderView struct {
+	ID    int
+	Items []StockItemView
+	Note  string
+}
+
+func TestMapper_Describe(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(priceFormatter{currency: "EUR"}.Format))
+
+	description, err := m.Describe(StockOrder{}, &StockOrderView{})
+	assert.NoError(t, err)
+	assert.Equal(t, `automapper_test.StockOrder -> automapper_test.StockOrderView
+  ID <- ID (sameTypes)
+  Items <- Items (slices)
+  Note <- unmapped
+
+automapper_test.StockItem -> automapper_test.StockItemView
+  SKU <- SKU (sameTypes)
+  Price <- Price (converterFunc: github.com/lebedevars/automapper_test.priceFormatter.Format-fm)
+  TTL <- TTL (converter is missing for types 'int -> time.Duration')
+`, description.String())
+
+	markdown := description.Markdown()
+	assert.Contains(t, markdown, "## `automapper_test.StockOrder` → `automapper_test.StockOrderView`\n\n")
+	assert.Contains(t, markdown, "| Items | `[]automapper_test.StockItemView` | Items | slices |  |  |\n")
+	assert.Contains(t, markdown, "| Note | `string` |  |  |  |  |\n")
+
+	_, err = m.Describe(StockOrder{}, 1)
+	assert.ErrorIs(t, err, automapper.ErrNotAStruct)
+}
+
+func TestMapper_WriteDOT(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(priceFormatter{currency: "EUR"}.Format))
+	assert.NoError(t, m.Map(&StockOrder{ID: 1, Items: []StockItem{{SKU: "a"}}}, &StockOrderView{}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.WriteDOT(&buf))
+	assert.Equal(t, `digraph automapper {
+	node [shape=box];
+	"automapper_test.StockItem";
+	"automapper_test.StockItemView";
+	"automapper_test.StockOrder";
+	"automapper_test.StockOrderView";
+	"int64";
+	"string";
+	"automapper_test.StockItem" -> "automapper_test.StockItemView" [label="3 fields", color=red];
+	"automapper_test.StockOrder" -> "automapper_test.StockOrderView" [label="2 fields"];
+	"int64" -> "string" [label="github.com/lebedevars/automapper_test.priceFormatter.Format-fm", style=dashed];
+}
+`, buf.String())
+}
+
+type Sample struct {
+	ID     int64
+	Name   string
+	Values []float64
+	Next   *Sample
+}
+
+type SampleView struct {
+	ID     int64
+	Name   string
+	Values []float64
+	Next   *Sample
+}
+
+type ReorderedSampleView struct {
+	Name   string
+	ID     int64
+	Values []float64
+	Next   *Sample
+}
+
+func TestMapper_Map_UnsafeCopy(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	from := Sample{ID: 1, Name: "a", Values: []float64{1, 2}, Next: &Sample{ID: 2}}
+	var view SampleView
+	err := m.Map(&from, &view, automapper.WithUnsafeCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, SampleView{ID: 1, Name: "a", Values: []float64{1, 2}, Next: from.Next}, view)
+
+	view = SampleView{Name: "b"}
+	err = m.Map(&Sample{ID: 3}, &view, automapper.WithUnsafeCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, SampleView{ID: 3, Name: "b"}, view)
+
+	var reordered ReorderedSampleView
+	err = m.Map(&from, &reordered, automapper.WithUnsafeCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, ReorderedSampleView{Name: "a", ID: 1, Values: []float64{1, 2}, Next: from.Next}, reordered)
+
+	var scoped SampleView
+	err = m.Map(&from, &scoped, automapper.WithUnsafeCopy(),
+		automapper.WithConverters(func(s string) string { return s + "!" }))
+	assert.NoError(t, err)
+	assert.Equal(t, "a!", scoped.Name)
+}
+
+type IndexedDoc struct {
+	ID    string
+	Title string
+}
+
+type IndexedDocView struct {
+	ID    string
+	Title string
+}
+
+// stackPool is a deterministic pool, unlike sync.Pool.
+type stackPool struct {
+	free []interface{}
+}
+
+func (p *stackPool) Get() interface{} {
+	if len(p.free) == 0 {
+		return nil
+	}
+
+	x := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+	return x
+}
+
+func (p *stackPool) Put(x interface{}) {
+	p.free = append(p.free, x)
+}
+
+func TestMapper_SetPool(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.ErrorIs(t, m.SetPool(IndexedDocView{}, &sync.Pool{}), automapper.ErrNotAPtr)
+
+	reused := &IndexedDocView{ID: "stale", Title: "stale"}
+	pool := &stackPool{free: []interface{}{reused}}
+	assert.NoError(t, m.SetPool((*IndexedDocView)(nil), pool))
+
+	var views []*IndexedDocView
+	err := m.Map(&[]*IndexedDoc{{ID: "1"}, {ID: "2", Title: "b"}}, &views)
+	assert.NoError(t, err)
+	assert.Equal(t, []*IndexedDocView{{ID: "1"}, {ID: "2", Title: "b"}}, views)
+	assert.Same(t, reused, views[0])
+
+	m.Release(views)
+	assert.Len(t, pool.free, 2)
+	assert.Equal(t, []*IndexedDocView{nil, nil}, views)
+}
+
+type PooledItem struct {
+	SKU string
+}
+
+type PooledOrder struct {
+	Items []*PooledItem
+	Extra *PooledItem
+}
+
+type PooledOrderDTO struct {
+	Items []*PooledItem
+	Extra *PooledItem
+}
+
+func TestMapper_Release_SharedWithSource(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	pool := &stackPool{free: []interface{}{&PooledItem{SKU: "stale"}}}
+	assert.NoError(t, m.SetPool((*PooledItem)(nil), pool))
+
+	from := PooledOrder{Items: []*PooledItem{{SKU: "a"}}, Extra: &PooledItem{SKU: "b"}}
+	var to PooledOrderDTO
+	assert.NoError(t, m.Map(&from, &to))
+	assert.Equal(t, PooledOrderDTO{Items: []*PooledItem{{SKU: "a"}}, Extra: &PooledItem{SKU: "b"}}, to)
+	assert.NotSame(t, from.Items[0], to.Items[0])
+	assert.NotSame(t, from.Extra, to.Extra)
+
+	m.Release(&to)
+	assert.Len(t, pool.free, 2)
+	assert.Equal(t, PooledOrderDTO{Items: []*PooledItem{nil}}, to)
+	assert.Equal(t, &PooledItem{SKU: "a"}, from.Items[0])
+	assert.Equal(t, &PooledItem{SKU: "b"}, from.Extra)
+
+	var same PooledOrder
+	assert.NoError(t, m.Map(&from, &same))
+	assert.Equal(t, from, same)
+	assert.NotSame(t, from.Items[0], same.Items[0])
+	assert.NotSame(t, from.Extra, same.Extra)
+	assert.Empty(t, pool.free)
+
+	m.Release(&same)
+	assert.Len(t, pool.free, 2)
+	assert.Equal(t, &PooledItem{SKU: "a"}, from.Items[0])
+	assert.Equal(t, &PooledItem{SKU: "b"}, from.Extra)
+}
+
+type TaggedQuery struct {
+	Page   int    `mapper:"page,omitempty"`
+	Cursor string `mapper:",omitepmty"`
+}
+
+type TaggedQueryView struct {
+	Page   int
+	Cursor string
+}
+
+func TestMapper_Map_InvalidTagOptions(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var view TaggedQueryView
+	err := m.Map(&TaggedQuery{Page: 1}, &view)
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+	assert.Contains(t, err.Error(), "'omitepmty'")
+
+	err = m.CreateMap(TaggedQuery{}, TaggedQueryView{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+
+	_, err = m.URLValues(&TaggedQuery{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+
+	err = m.Map(&TaggedQueryView{}, &struct {
+		Page int `mapper:",csv"`
+	}{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+
+	err = m.Map(&TaggedQueryView{}, &struct {
+		Page int `mapper:",omitempty=true"`
+	}{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+
+	err = m.Map(&TaggedQueryView{Page: 2}, &struct {
+		Page int `mapper:"Page,omitempty"`
+	}{})
+	assert.NoError(t, err)
+}
+
+type AuditedInput struct {
+	Name      *string
+	Owner     *string
+	CreatedAt time.Time
+}
+
+type AuditedRecord struct {
+	Name      string
+	Owner     string    `mapper:",skipzero"`
+	CreatedAt time.Time `mapper:",skipzero"`
+}
+
+func TestMapper_Map_SkipZeroTag(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithGraphQL(automapper.NilZero))
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := AuditedRecord{Name: "a", Owner: "alice", CreatedAt: created}
+	err := m.Map(&AuditedInput{}, &record)
+	assert.NoError(t, err)
+	assert.Equal(t, AuditedRecord{Owner: "alice", CreatedAt: created}, record)
+
+	bob := "bob"
+	err = m.Map(&AuditedInput{Owner: &bob}, &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", record.Owner)
+
+	err = m.Patch(map[string]interface{}{"Owner": nil, "Name": nil}, &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", record.Owner)
+
+	var fresh AuditedRecord
+	err = m.Map(&AuditedInput{CreatedAt: created}, &fresh)
+	assert.NoError(t, err)
+	assert.Equal(t, created, fresh.CreatedAt)
+}
+
+type UpstreamCustomer struct {
+	Email   string
+	Address *UpstreamAddress
+}
+
+type UpstreamAddress struct {
+	City string
+}
+
+type Customer struct {
+	Email   string `mapper:",require"`
+	Phone   string
+	Address *Address
+}
+
+type Address struct {
+	City    string `mapper:",require"`
+	Country string `mapper:",require"`
+}
+
+func TestMapper_Map_RequireTag(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var customer Customer
+	err := m.Map(&UpstreamCustomer{Email: "a@b.c"}, &customer)
+	assert.NoError(t, err)
+	assert.Equal(t, "a@b.c", customer.Email)
+
+	err = m.Map(&UpstreamCustomer{}, &customer)
+	var missing *automapper.MissingSourceError
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, "Email", missing.Path)
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+
+	err = m.Map(&UpstreamCustomer{Email: "a@b.c", Address: &UpstreamAddress{City: "x"}}, &customer)
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, "Address.Country", missing.Path)
+
+	err = m.CreateMap(UpstreamCustomer{}, Customer{})
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+	assert.Contains(t, err.Error(), "Address.Country")
+}
+
+type SignupForm struct {
+	Email string
+	Code  string
+	Name  string
+	Age   int
+}
+
+type Signup struct {
+	Email string `mapper:"Email,trim,lower"`
+	Code  string `mapper:",upper"`
+	Name  string `mapper:",trim"`
+}
+
+func TestMapper_Map_StringTransformTags(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var signup Signup
+	err := m.Map(&SignupForm{Email: "  Alice@Example.COM ", Code: "ab1", Name: " Alice "}, &signup)
+	assert.NoError(t, err)
+	assert.Equal(t, Signup{Email: "alice@example.com", Code: "AB1", Name: "Alice"}, signup)
+
+	same := Signup{}
+	err = m.Map(&Signup{Email: " A "}, &same)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", same.Email)
+
+	err = m.Map(&SignupForm{}, &struct {
+		Age int `mapper:",trim"`
+	}{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+}
+
+type Slug struct {
+	Title string
+	Body  string
+}
+
+type SlugView struct {
+	Title string `mapper:",converter=slug"`
+	Body  string
+}
+
+func TestMapper_SetNamed(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.ErrorIs(t, m.SetNamed("slug", "slug"), automapper.ErrNotAFn)
+	assert.NoError(t, m.SetNamed("slug", func(s string) string { return strings.ReplaceAll(strings.ToLower(s), " ", "-") }))
+
+	var view SlugView
+	err := m.Map(&Slug{Title: "Hello World", Body: "Hello World"}, &view,
+		automapper.WithConverters(func(s string) string { return s + "!" }))
+	assert.NoError(t, err)
+	assert.Equal(t, SlugView{Title: "hello-world", Body: "Hello World!"}, view)
+
+	err = m.Map(&Slug{}, &struct {
+		Title string `mapper:",converter=missing"`
+	}{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+
+	err = m.Map(&Slug{}, &struct {
+		Title int `mapper:",converter=slug"`
+	}{})
+	assert.ErrorIs(t, err, automapper.ErrInvalidTag)
+}
+
+type FeatureForm struct {
+	Enabled string
+	Beta    string
+	Public  bool
+}
+
+type FeatureFlags struct {
+	Enabled bool
+	Beta    bool
+	Public  string
+}
+
+func TestMapper_Map_BoolStrings(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var flags FeatureFlags
+	err := m.Map(&FeatureForm{Enabled: "Yes", Beta: "0", Public: true}, &flags, automapper.WithBoolStrings(automapper.DefaultBoolStrings))
+	assert.NoError(t, err)
+	assert.Equal(t, FeatureFlags{Enabled: true, Public: "true"}, flags)
+
+	err = m.Map(&FeatureForm{Enabled: "maybe"}, &flags, automapper.WithBoolStrings(automapper.DefaultBoolStrings))
+	var convErr *automapper.ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Enabled", convErr.Path)
+
+	onOff := automapper.BoolStrings{True: []string{"on"}, FormatTrue: "on", FormatFalse: "off"}
+	var form FeatureForm
+	err = m.Map(&FeatureFlags{Enabled: true}, &form, automapper.WithBoolStrings(onOff))
+	assert.NoError(t, err)
+	assert.Equal(t, "on", form.Enabled)
+
+	err = m.Map(&FeatureForm{Enabled: "ON", Beta: "maybe"}, &flags, automapper.WithBoolStrings(onOff))
+	assert.NoError(t, err)
+	assert.True(t, flags.Enabled)
+	assert.False(t, flags.Beta)
+}
+
+type FeatureMatrix struct {
+	Stages  []bool
+	Regions map[string]string
+}
+
+type FeatureMatrixForm struct {
+	Stages  []string
+	Regions map[string]bool
+}
+
+func TestMapper_Map_BoolStrings_Collections(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	var form FeatureMatrixForm
+	err := m.Map(&FeatureMatrix{Stages: []bool{true, false}, Regions: map[string]string{"eu": "yes", "us": "no"}}, &form,
+		automapper.WithBoolStrings(automapper.DefaultBoolStrings))
+	assert.NoError(t, err)
+	assert.Equal(t, FeatureMatrixForm{Stages: []string{"true", "false"}, Regions: map[string]bool{"eu": true, "us": false}}, form)
+
+	err = m.Map(&FeatureMatrix{Regions: map[string]string{"eu": "maybe"}}, &form, automapper.WithBoolStrings(automapper.DefaultBoolStrings))
+	var convErr *automapper.ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Regions[eu]", convErr.Path)
+}
+
+// Amount mimics money types with unexported fields, which can't be mapped field by field.
+type Amount struct {
+	cents    int64
+	currency string
+}
+
+type AmountProto struct {
+	CurrencyCode string
+	Units        int64
+	Nanos        int32
+}
+
+type Invoice struct {
+	Total    Amount
+	Discount *Amount
+	Tax      AmountProto
+}
+
+type InvoiceDTO struct {
+	TotalAmount      int64
+	TotalCurrency    string
+	DiscountAmount   int
+	DiscountCurrency string
+	Tax              Amount
+}
+
+func TestRegisterMoney(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.ErrorIs(t, automapper.RegisterMoney(m, automapper.MoneyCodec[Amount]{}), automapper.ErrNilValue)
+	assert.NoError(t, automapper.RegisterMoney(m, automapper.MoneyCodec[Amount]{
+		Split: func(money Amount) (int64, string) { return money.cents, money.currency },
+		Join: func(amount int64, currency string) (Amount, error) {
+			if len(currency) != 3 {
+				return Amount{}, fmt.Errorf("invalid currency %q", currency)
+			}
+
+			return Amount{cents: amount, currency: currency}, nil
+		},
+	}))
+	assert.NoError(t, automapper.RegisterMoney(m, automapper.MoneyCodec[AmountProto]{
+		Split: func(money AmountProto) (int64, string) {
+			return money.Units*100 + int64(money.Nanos/10_000_000), money.CurrencyCode
+		},
+		Join: func(amount int64, currency string) (AmountProto, error) {
+			return AmountProto{CurrencyCode: currency, Units: amount / 100, Nanos: int32(amount%100) * 10_000_000}, nil
+		},
+	}))
+
+	invoice := Invoice{
+		Total:    Amount{cents: 1250, currency: "EUR"},
+		Discount: &Amount{cents: 50, currency: "EUR"},
+		Tax:      AmountProto{CurrencyCode: "EUR", Units: 2, Nanos: 500_000_000},
+	}
+	var dto InvoiceDTO
+	err := m.Map(&invoice, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, InvoiceDTO{
+		TotalAmount:      1250,
+		TotalCurrency:    "EUR",
+		DiscountAmount:   50,
+		DiscountCurrency: "EUR",
+		Tax:              Amount{cents: 250, currency: "EUR"},
+	}, dto)
+
+	var back Invoice
+	err = m.Map(&dto, &back)
+	assert.NoError(t, err)
+	assert.Equal(t, invoice, back)
+
+	err = m.Map(&InvoiceDTO{TotalAmount: 1, TotalCurrency: "E"}, &back)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+type AccountV1 struct {
+	Name string
+}
+
+type AccountV2 struct {
+	Name   string
+	Status string
+}
+
+type AccountStatus int
+
+type AccountV3 struct {
+	Name   string
+	Status AccountStatus
+}
+
+func TestMapper_MapVersioned(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	// accounts of V1 are active
+	assert.NoError(t, m.SetFactory(func() *AccountV2 { return &AccountV2{Status: "active"} }))
+	assert.NoError(t, m.Set(func(status string) AccountStatus {
+		if status == "active" {
+			return 1
+		}
+
+		return 0
+	}))
+
+	var v3 AccountV3
+	err := m.MapVersioned(&AccountV1{Name: "a"}, &v3)
+	assert.ErrorIs(t, err, automapper.ErrMissingChain)
+
+	assert.NoError(t, m.CreateMap(AccountV1{}, AccountV2{}))
+	assert.NoError(t, m.CreateMap(AccountV2{}, AccountV3{}))
+	err = m.MapVersioned(&AccountV1{Name: "a"}, &v3)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountV3{Name: "a", Status: 1}, v3)
+
+	var v1 AccountV1
+	err = m.MapVersioned(&v3, &v1)
+	assert.ErrorIs(t, err, automapper.ErrMissingChain)
+}
+
+type Cents int64
+
+type LedgerRow struct {
+	ID    int
+	Total string
+}
+
+type LedgerEntry struct {
+	ID    int
+	Total Cents
+}
+
+type LedgerEntryDTO struct {
+	ID    int
+	Total float64
+}
+
+func TestMapVia(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.Set(func(s string) (Cents, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		return Cents(math.Round(f * 100)), err
+	}))
+	assert.NoError(t, m.Set(func(c Cents) float64 { return float64(c) / 100 }))
+
+	var dto LedgerEntryDTO
+	err := m.Map(&LedgerRow{ID: 1, Total: "12.5"}, &dto)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+
+	err = automapper.MapVia[LedgerEntry](m, &LedgerRow{ID: 1, Total: "12.5"}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, LedgerEntryDTO{ID: 1, Total: 12.5}, dto)
+
+	err = automapper.MapVia[LedgerEntry](m, &LedgerRow{ID: 1, Total: "x"}, &dto)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.Contains(t, err.Error(), "LedgerRow -> automapper_test.LedgerEntry")
+}
+
+type Centigrade float64
+
+type SensorReading struct {
+	Sensor string
+	Temp   float64
+}
+
+type SensorView struct {
+	Sensor string `mapper:",converter=upper"`
+	Temp   Centigrade
+}
+
+func TestMapper_Include(t *testing.T) {
+	t.Parallel()
+	toCentigrade := func(f float64) Centigrade { return Centigrade((f - 32) * 5 / 9) }
+	lib := automapper.New()
+	assert.NoError(t, lib.Set(toCentigrade))
+	assert.NoError(t, lib.SetNamed("upper", strings.ToUpper))
+	assert.NoError(t, lib.CreateMap(SensorReading{}, SensorView{}))
+
+	app := automapper.New()
+	assert.NoError(t, app.Set(toCentigrade))
+	assert.NoError(t, app.Include(lib))
+
+	var view SensorView
+	assert.NoError(t, app.Map(&SensorReading{Sensor: "kitchen", Temp: 212}, &view))
+	assert.Equal(t, SensorView{Sensor: "KITCHEN", Temp: 100}, view)
+	// declared pairs are included
+	assert.NoError(t, app.MapVersioned(&SensorReading{Sensor: "attic"}, &view))
+
+	conflicting := automapper.New()
+	assert.NoError(t, conflicting.Set(func(f float64) Centigrade { return Centigrade(f) }))
+	assert.NoError(t, conflicting.SetNamed("upper", strings.ToLower))
+	assert.NoError(t, conflicting.SetNamed("lower", strings.ToLower))
+	err := app.Include(conflicting)
+	assert.ErrorIs(t, err, automapper.ErrConflict)
+	assert.Contains(t, err.Error(), "converter 'float64 -> automapper_test.Centigrade'")
+	assert.Contains(t, err.Error(), "named converter 'upper'")
+
+	// m is unchanged on conflicts
+	view = SensorView{}
+	assert.NoError(t, app.Map(&SensorReading{Sensor: "hall", Temp: 32}, &view))
+	assert.Equal(t, SensorView{Sensor: "HALL"}, view)
+}
+
+type CatalogProfile struct {
+	Currency string
+}
+
+func (p CatalogProfile) Configure(m *automapper.Mapper) error {
+	if err := m.Set(func(cents int) string { return fmt.Sprintf("%d.%02d %s", cents/100, cents%100, p.Currency) }); err != nil {
+		return err
+	}
+
+	return m.CreateMap(CatalogItem{}, CatalogItemView{})
+}
+
+type CatalogItem struct {
+	Title string
+	Price int
+}
+
+type CatalogItemView struct {
+	Title string
+	Price string
+}
+
+func TestMapper_AddProfile(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.AddProfile(CatalogProfile{Currency: "EUR"}))
+
+	var view CatalogItemView
+	assert.NoError(t, m.Map(&CatalogItem{Title: "Lamp", Price: 1999}, &view))
+	assert.Equal(t, CatalogItemView{Title: "Lamp", Price: "19.99 EUR"}, view)
+
+	failing := automapper.ProfileFunc(func(m *automapper.Mapper) error {
+		return m.Set("not a converter")
+	})
+	err := m.AddProfile(failing, CatalogProfile{})
+	assert.ErrorIs(t, err, automapper.ErrNotAFn)
+	assert.Contains(t, err.Error(), "profile automapper.ProfileFunc")
+}
+
+type ShipmentLine struct {
+	SKU    string
+	Weight float64
+}
+
+type ShipmentLineDTO struct {
+	SKU    string
+	Weight time.Duration
+	Note   string `mapper:",require"`
+}
+
+type Shipment struct {
+	Lines []ShipmentLine
+}
+
+type ShipmentDTO struct {
+	Lines []ShipmentLineDTO
+}
+
+func TestMapper_AssertConfigurationIsValid(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.AssertConfigurationIsValid())
+
+	assert.Error(t, m.CreateMap(ShipmentLine{}, ShipmentLineDTO{}))
+	assert.Error(t, m.CreateMap(Shipment{}, ShipmentDTO{}))
+	err := m.AssertConfigurationIsValid()
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+	// nested pairs are reported once
+	assert.Equal(t, 1, strings.Count(err.Error(), "ShipmentLineDTO.Weight"))
+	assert.Equal(t, 1, strings.Count(err.Error(), "ShipmentLineDTO.Note"))
+
+	assert.NoError(t, m.Set(func(f float64) time.Duration { return time.Duration(f * float64(time.Second)) }))
+	err = m.AssertConfigurationIsValid()
+	assert.NotErrorIs(t, err, automapper.ErrMissingConverter)
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+}
+
+type Member struct {
+	Name  string
+	Email string
+}
+
+type MemberDTO struct {
+	Name string
+}
+
+type MemberResponse struct {
+	Name   string
+	Joined time.Time `mapper:",require"`
+}
+
+func TestMapper_DiscoverPairs(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := m.DiscoverPairs(nil, Member{}, &MemberDTO{}, MemberResponse{}, Celsius(0))
+	assert.ErrorIs(t, err, automapper.ErrNotAStruct)
+
+	err = m.DiscoverPairs(nil, Member{}, &MemberDTO{}, MemberResponse{}, EpochEvent{})
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+	assert.Contains(t, err.Error(), "MemberResponse.Joined")
+
+	var dto MemberDTO
+	assert.NoError(t, m.MapVersioned(&Member{Name: "ann", Email: "ann@example.com"}, &dto))
+	assert.Equal(t, MemberDTO{Name: "ann"}, dto)
+
+	var member Member
+	assert.NoError(t, m.MapVersioned(&dto, &member))
+	assert.Equal(t, Member{Name: "ann"}, member)
+
+	err = m.MapVersioned(&EpochEvent{}, &member)
+	assert.ErrorIs(t, err, automapper.ErrMissingChain)
+
+	m = automapper.New()
+	assert.NoError(t, m.DiscoverPairs([]string{"DTO"}, Member{}, MemberDTO{}, MemberResponse{}))
+	assert.NoError(t, m.AssertConfigurationIsValid())
+}
+
+type ProfileRecord struct {
+	Name     string
+	Nickname string
+	Password string `mapper:",ignore"`
+	Settings map[string]string
+}
+
+type ProfileView struct {
+	Name     string
+	Password string
+	Settings map[string]string `mapper:",ignore"`
+}
+
+func TestMapper_AssertConfigurationIsValid_UnusedSources(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	assert.NoError(t, m.CreateMap(ProfileRecord{}, ProfileView{}))
+	assert.NoError(t, m.AssertConfigurationIsValid())
+
+	err := m.AssertConfigurationIsValid(automapper.ReportUnusedSources())
+	assert.ErrorIs(t, err, automapper.ErrUnusedSource)
+	assert.Contains(t, err.Error(), "ProfileRecord.Nickname")
+	assert.Contains(t, err.Error(), "ProfileRecord.Settings")
+	assert.NotContains(t, err.Error(), "ProfileRecord.Password")
+
+	var view ProfileView
+	record := ProfileRecord{Name: "ann", Password: "secret", Settings: map[string]string{"theme": "dark"}}
+	assert.NoError(t, m.Map(&record, &view))
+	assert.Equal(t, ProfileView{Name: "ann"}, view)
+}
+
+type OrderPlacedEvent struct {
+	OrderID  string
+	Customer OrderPlacedCustomer
+	TraceID  string `mapper:",ignore"`
+}
+
+type OrderPlacedCustomer struct {
+	ID    string
+	Email string
+}
+
+type OrderPlacedMessage struct {
+	OrderID  string
+	Customer OrderPlacedRecipient
+}
+
+type OrderPlacedRecipient struct {
+	ID string
+}
+
+func TestMapper_Map_StrictSource(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	event := OrderPlacedEvent{OrderID: "o1", Customer: OrderPlacedCustomer{ID: "c1"}, TraceID: "t1"}
+	var msg OrderPlacedMessage
+	assert.NoError(t, m.Map(&event, &msg))
+	assert.Equal(t, OrderPlacedMessage{OrderID: "o1", Customer: OrderPlacedRecipient{ID: "c1"}}, msg)
+
+	err := m.Map(&event, &msg, automapper.WithStrictSource())
+	assert.ErrorIs(t, err, automapper.ErrUnusedSource)
+	assert.Contains(t, err.Error(), "Email of 'automapper_test.OrderPlacedCustomer'")
+	assert.NotContains(t, err.Error(), "TraceID")
+
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeUnusedSource, mapErr.Code)
+	assert.Equal(t, "Customer", mapErr.Path)
+}
+
+type SquashedBase struct {
+	ID int
+}
+
+type SquashedOrder struct {
+	SquashedBase `mapper:",squash"`
+	Placed       time.Time `mapper:",timefmt=2006-01-02"`
+	Customer     string    `mapper:",required"`
+}
+
+type SquashedOrderDTO struct {
+	ID       int
+	Placed   string
+	Customer string
+}
+
+func TestMapper_Map_SquashRequiredTimeFormat(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	order := SquashedOrder{SquashedBase: SquashedBase{ID: 1}, Placed: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Customer: "c"}
+
+	var dto SquashedOrderDTO
+	err := m.Map(&order, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, SquashedOrderDTO{ID: 1, Placed: "2020-01-02", Customer: "c"}, dto)
+
+	var mapped SquashedOrder
+	err = m.Map(&dto, &mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, order, mapped)
+
+	// required is an alias of require
+	err = m.Map(&SquashedOrderDTO{ID: 1}, &mapped)
+	assert.ErrorIs(t, err, automapper.ErrMissingSource)
+
+	err = m.Map(&SquashedOrderDTO{Placed: "02.01.2020", Customer: "c"}, &mapped)
+	var mapErr *automapper.Error
+	if assert.True(t, errors.As(err, &mapErr)) {
+		assert.Equal(t, automapper.CodeConverterFailed, mapErr.Code)
+		assert.Equal(t, "Placed", mapErr.Path)
+	}
+}
+
+type EpochEvent struct {
+	Created time.Time `mapper:",unix"`
+	Updated time.Time
+	Expires time.Time
+}
+
+type EpochEventDTO struct {
+	Created int64
+	Updated int64 `mapper:",unixms"`
+	Expires int
+}
+
+func TestMapper_Map_UnixTime(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithTimeLocation(time.UTC))
+	event := EpochEvent{
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Updated: time.Date(2020, 1, 2, 3, 4, 5, 6e6, time.UTC),
+	}
+
+	dto := EpochEventDTO{}
+	err := m.Map(&event, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, EpochEventDTO{Created: 1577934245, Updated: 1577934245006}, dto)
+
+	mapped := EpochEvent{}
+	err = m.Map(&dto, &mapped)
+	assert.NoError(t, err)
+	assert.Equal(t, event, mapped)
+
+	// fields without tag options need converters
+	err = m.Map(&EpochEvent{Expires: event.Created}, &dto)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type JSONEnvelope struct {
+	Count   json.Number
+	Price   json.Number
+	ID      json.Number
+	Payload json.RawMessage
+	Meta    json.RawMessage
+}
+
+type JSONEnvelopeDTO struct {
+	Count   int
+	Price   float64
+	ID      string
+	Payload *Simple1
+	Meta    map[string]int
+}
+
+func TestMapper_Map_JSON(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	envelope := JSONEnvelope{
+		Count:   "3",
+		Price:   "1.5",
+		ID:      "42",
+		Payload: json.RawMessage(`{"Int":1,"String":"s"}`),
+		Meta:    json.RawMessage(`{"a":1}`),
+	}
+
+	dto := JSONEnvelopeDTO{}
+	err := m.Map(&envelope, &dto, automapper.Except("Payload", "Meta"))
+	assert.NoError(t, err)
+	assert.Equal(t, JSONEnvelopeDTO{Count: 3, Price: 1.5, ID: "42"}, dto)
+
+	err = m.Map(&envelope, &dto)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+
+	err = m.Map(&envelope, &dto, automapper.WithJSONRawMessages())
+	assert.NoError(t, err)
+	assert.Equal(t, &Simple1{Int: 1, String: "s"}, dto.Payload)
+	assert.Equal(t, map[string]int{"a": 1}, dto.Meta)
+
+	mapped := JSONEnvelope{}
+	err = m.Map(&dto, &mapped, automapper.WithJSONRawMessages(), automapper.Only("Payload", "Meta"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Int":1,"String":"s","Float64":0,"Time":"0001-01-01T00:00:00Z"}`, string(mapped.Payload))
+	assert.JSONEq(t, `{"a":1}`, string(mapped.Meta))
+
+	err = m.Map(&JSONEnvelope{Count: "1.5"}, &dto)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+type SearchParams struct {
+	Page  string
+	Limit string
+	Ratio string
+	Total uint
+}
+
+type Search struct {
+	Page  int
+	Limit int32
+	Ratio float64
+	Total string
+}
+
+func TestMapper_Map_StringNumberConversions(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	params := SearchParams{Page: "2", Limit: "-50", Ratio: "0.25", Total: 1000}
+
+	search := Search{}
+	err := m.Map(&params, &search)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+
+	err = m.Map(&params, &search, automapper.WithStringNumberConversions())
+	assert.NoError(t, err)
+	assert.Equal(t, Search{Page: 2, Limit: -50, Ratio: 0.25, Total: "1000"}, search)
+
+	mapped := SearchParams{}
+	err = m.Map(&Search{Page: 2, Limit: -50, Ratio: 0.25, Total: "1000"}, &mapped, automapper.WithStringNumberConversions())
+	assert.NoError(t, err)
+	assert.Equal(t, params, mapped)
+
+	err = m.Map(&struct{ Query SearchParams }{SearchParams{Limit: "many"}}, &struct{ Query Search }{},
+		automapper.WithStringNumberConversions())
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	var convErr *automapper.ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Query.Limit", convErr.Path)
+	assert.Equal(t, "many", convErr.Value)
+}
+
+type SearchFilters struct {
+	IDs   []string
+	Page  *string
+	Sizes map[string]string
+}
+
+type SearchFilter struct {
+	IDs   []int
+	Page  int
+	Sizes map[string]uint16
+}
+
+func TestMapper_Map_StringNumberConversions_Collections(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	page := "3"
+	var filter SearchFilter
+	err := m.Map(&SearchFilters{IDs: []string{"1", "2"}, Page: &page, Sizes: map[string]string{"s": "10"}}, &filter,
+		automapper.WithStringNumberConversions())
+	assert.NoError(t, err)
+	assert.Equal(t, SearchFilter{IDs: []int{1, 2}, Page: 3, Sizes: map[string]uint16{"s": 10}}, filter)
+
+	err = m.Map(&SearchFilters{IDs: []string{"1", "x"}}, &filter, automapper.WithStringNumberConversions())
+	var convErr *automapper.ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "IDs[1]", convErr.Path)
+
+	var filters SearchFilters
+	err = m.Map(&SearchFilter{IDs: []int{4}}, &filters, automapper.WithStringNumberConversions())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4"}, filters.IDs)
+}
+
+type Person struct {
+	BirthDate string `mapper:"birth_date"`
+}
+
+type PersonDTO struct {
+	BirthDate time.Time `mapper:"birth_date"`
+}
+
+type Family struct {
+	Members []Person
+}
+
+type FamilyDTO struct {
+	Members []PersonDTO
+}
+
+var errBirthDate = errors.New("birth_date must be YYYY-MM-DD")
+
+func TestMapper_SetErrorTranslator(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := m.Set(func(s string) (time.Time, error) { return time.Parse("2006-01-02", s) })
+	assert.NoError(t, err)
+
+	var paths []string
+	m.SetErrorTranslator(func(fieldPath string, err error) error {
+		paths = append(paths, fieldPath)
+		if errors.Is(err, automapper.ErrConverter) && strings.HasSuffix(fieldPath, "birth_date") {
+			return fmt.Errorf("%s: %w", fieldPath, errBirthDate)
+		}
+
+		return nil
+	})
+
+	err = m.Map(&Family{Members: []Person{{"2000-01-01"}, {"01.01.2000"}}}, &FamilyDTO{})
+	assert.ErrorIs(t, err, errBirthDate)
+	assert.EqualError(t, err, "Members[1].birth_date: birth_date must be YYYY-MM-DD")
+
+	// errors translated into nil are returned as is
+	err = m.Map(&Family{}, FamilyDTO{})
+	assert.ErrorIs(t, err, automapper.ErrNotAPtr)
+	assert.Equal(t, []string{"Members[1].birth_date", ""}, paths)
+}
+
+func TestMapper_Map_ErrorCodes(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := m.Set(func(s string) (int, error) { return strconv.Atoi(s) })
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		from, to interface{}
+		opts     []automapper.Option
+		code     automapper.ErrorCode
+		path     string
+	}{
+		{"missing converter", &EpochEvent{Expires: time.Now()}, &EpochEventDTO{}, nil, automapper.CodeMissingConverter, "Expires"},
+		{"overflow", &struct{ V int }{1 << 40}, &struct{ V int8 }{}, []automapper.Option{automapper.WithStrictNumeric()},
+			automapper.CodeOverflow, "V"},
+		{"converter failed", &struct{ V []string }{[]string{"1", "x"}}, &struct{ V []int }{}, nil,
+			automapper.CodeConverterFailed, "V[1]"},
+		{"unsupported type", 1, &struct{}{}, nil, automapper.CodeUnsupportedType, ""},
+		{"max depth", &Node1{Next: &Node1{Value: 1}}, &Node2{}, []automapper.Option{automapper.WithMaxDepth(1)},
+			automapper.CodeMaxDepth, "Next"},
+		{"invalid tag", &struct{ V int }{1}, &struct {
+			V int `mapper:",bogus"`
+		}{}, nil, automapper.CodeInvalidConfig, "V"},
+	}
+	for _, tt := range tests {
+		err := m.Map(tt.from, tt.to, tt.opts...)
+		var mapErr *automapper.Error
+		if assert.True(t, errors.As(err, &mapErr), tt.name) {
+			assert.Equal(t, tt.code, mapErr.Code, tt.name)
+			assert.Equal(t, tt.path, mapErr.Path, tt.name)
+		}
+	}
+}
+
+func TestMapper_ErrorCodes_EntryPoints(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	var mapErr *automapper.Error
+	err := m.Patch([]string{"x"}, &Simple2{})
+	assert.ErrorIs(t, err, automapper.ErrUnsupportedPatch)
+	if assert.True(t, errors.As(err, &mapErr)) {
+		assert.Equal(t, automapper.CodeUnsupportedType, mapErr.Code)
+	}
+
+	err = m.MapURLValues(url.Values{"Time": {"2020-01-01"}}, &Simple2{})
+	if assert.True(t, errors.As(err, &mapErr)) {
+		assert.Equal(t, automapper.CodeMissingConverter, mapErr.Code)
+	}
+
+	_, err = m.URLValues(Simple1{})
+	assert.ErrorIs(t, err, automapper.ErrNotAPtr)
+	if assert.True(t, errors.As(err, &mapErr)) {
+		assert.Equal(t, automapper.CodeUnsupportedType, mapErr.Code)
+	}
+}
+
+func TestMapper_Stats(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	err := m.Set(strconv.Itoa)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		err = m.Map(&Converters1{Field1: i + 1}, &Converters2{})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, automapper.Stats{PlanHits: 2, PlanMisses: 1, Plans: 1, ConverterCalls: 3}, m.Stats())
+}
+
+func TestMapper_Map_PlanCacheSize(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithPlanCacheSize(2))
+	for _, to := range []interface{}{&Simple2{}, &Converters1{}, &Simple2{}, &Masked2{}, &Simple2{}} {
+		err := m.Map(&Simple1{Int: 1}, to)
+		assert.NoError(t, err)
+	}
+
+	// Simple1 -> Converters1 plan is evicted by Simple1 -> Masked2, Simple1 -> Simple2 is kept as recently used
+	stats := m.Stats()
+	assert.Equal(t, 2, stats.Plans)
+	assert.Equal(t, uint64(1), stats.PlanEvictions)
+	assert.Equal(t, uint64(2), stats.PlanHits)
+}
+
+type cancelingConverter struct {
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (c *cancelingConverter) convert(i int) string {
+	c.calls++
+	if c.calls == 10 && c.cancel != nil {
+		c.cancel()
+	}
+
+	return strconv.Itoa(i)
+}
+
+func TestMapper_MapCtx(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	converter := &cancelingConverter{}
+	m := automapper.New()
+	err := m.Set(converter.convert)
+	assert.NoError(t, err)
+
+	from := struct{ Items []int }{make([]int, 5000)}
+	for i := range from.Items {
+		from.Items[i] = i + 1
+	}
+
+	to := struct{ Items []string }{}
+	err = m.MapCtx(context.Background(), &from, &to)
+	assert.NoError(t, err)
+	assert.Len(t, to.Items, 5000)
+
+	converter.calls, converter.cancel = 0, cancel
+	err = m.MapCtx(ctx, &from, &to)
+	assert.ErrorIs(t, err, context.Canceled)
+	// mapping stops at the next check
+	assert.Equal(t, 1024, converter.calls)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeCanceled, mapErr.Code)
+	assert.Equal(t, "Items", mapErr.Path)
+
+	err = m.MapCtx(ctx, &from, &to)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+var errStopChunks = errors.New("stop")
+
+func TestMapper_MapChunks(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	from := make([]Simple1, 10)
+	for i := range from {
+		from[i].Int = i
+	}
+
+	var progress [][2]int
+	to := []Simple2{}
+	err := m.MapChunks(&from, &to, 4, func(mapped, total int) error {
+		progress = append(progress, [2]int{mapped, total})
+		assert.Len(t, to, mapped)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{4, 10}, {8, 10}, {10, 10}}, progress)
+	assert.Len(t, to, 10)
+	assert.Equal(t, 9, to[9].Int)
+
+	// progress errors stop mapping
+	err = m.MapChunks(&from, &to, 3, func(mapped, total int) error {
+		return errStopChunks
+	})
+	assert.Equal(t, errStopChunks, err)
+	assert.Len(t, to, 3)
+
+	// index paths are not relative to chunks
+	failing := []Converters1{{}, {}, {}, {Field1: 2}}
+	err = m.MapChunks(&failing, &[]Converters2{}, 3, nil)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "[3].Field1", mapErr.Path)
+
+	err = m.MapChunks(&Simple1{}, &to, 3, nil)
+	assert.ErrorIs(t, err, automapper.ErrUnsupportedMapping)
+}
+
+func TestMapper_MapEach(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	from := []Simple1{{Int: 1, String: "a"}, {Int: 2}, {}}
+
+	var mapped []Simple2
+	err := m.MapEach(from, func(to Simple2) error {
+		mapped = append(mapped, to)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Simple2{{Int: 1, String: "a"}, {Int: 2}, {}}, mapped)
+
+	// pointer destination is reused and reset for every element
+	var pointers []*Simple2
+	err = m.MapEach(&from, func(to *Simple2) error {
+		pointers = append(pointers, to)
+		if to.Int == 2 {
+			assert.Empty(t, to.String)
+			return errStopChunks
+		}
+
+		return nil
+	})
+	assert.Equal(t, errStopChunks, err)
+	assert.Len(t, pointers, 2)
+	assert.Same(t, pointers[0], pointers[1])
+
+	err = m.MapEach([]Converters1{{}, {Field1: 1}}, func(to Converters2) error { return nil })
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "[1].Field1", mapErr.Path)
+
+	err = m.MapEach(from, func(to Simple2) {})
+	assert.ErrorIs(t, err, automapper.ErrNotAFn)
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeInvalidConfig, mapErr.Code)
+}
+
+type EachCustomer struct {
+	Name string
+}
+
+type EachOrder struct {
+	Customer *EachCustomer
+}
+
+type EachCustomerDTO struct {
+	Name string
+}
+
+type EachOrderDTO struct {
+	Customer *EachCustomerDTO
+}
+
+func TestMapper_MapEach_SharedPointer(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	shared := &EachCustomer{Name: "a"}
+	from := []EachOrder{{Customer: shared}, {Customer: shared}}
+
+	var customers []*EachCustomerDTO
+	err := m.MapEach(from, func(to EachOrderDTO) error {
+		customers = append(customers, to.Customer)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, customers, 2)
+	assert.Equal(t, &EachCustomerDTO{Name: "a"}, customers[0])
+	assert.Equal(t, &EachCustomerDTO{Name: "a"}, customers[1])
+	assert.NotSame(t, customers[0], customers[1])
+}
+
+type Masked1 struct {
+	DisplayName string
+	Email       string
+	Nested      Simple1
+	Items       []Simple1
+}
+
+type Masked2 struct {
+	DisplayName string
+	Email       string
+	Nested      Simple2
+	Items       []Simple2
+}
+
+func TestMapper_MapMasked(t *testing.T) {
+	t.Parallel()
+	from := Masked1{
+		DisplayName: "name",
+		Email:       "email",
+		Nested:      Simple1{Int: 1, String: "string"},
+		Items:       []Simple1{{Int: 1, String: "string"}},
+	}
+	to := Masked2{Email: "old"}
+
+	m := automapper.New()
+	err := m.MapMasked(&from, &to, []string{"display_name", "nested.int", "items.string"})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Masked2{
+		DisplayName: "name",
+		Email:       "old",
+		Nested:      Simple2{Int: 1},
+		Items:       []Simple2{{String: "string"}},
+	}, to)
+}
+
+func TestMapper_MapMasked_EmptyMask(t *testing.T) {
+	t.Parallel()
+	from := Masked1{DisplayName: "name", Email: "email"}
+	to := Masked2{}
+
+	m := automapper.New()
+	err := m.MapMasked(&from, &to, nil)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, from.DisplayName, to.DisplayName)
+	assert.EqualValues(t, from.Email, to.Email)
+}
+
+type Patch1 struct {
+	DisplayName *string
+	Email       *string
+	Nested      *Simple1
+}
+
+type Patch2 struct {
+	DisplayName string
+	Email       string
+	Nested      *Simple2
+}
+
+func TestMapper_Patch_Struct(t *testing.T) {
+	t.Parallel()
+	name := "name"
+	from := Patch1{DisplayName: &name}
+	to := Patch2{DisplayName: "old", Email: "email"}
+
+	m := automapper.New()
+	err := m.Patch(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Patch2{DisplayName: "name", Email: "email"}, to)
+}
+
+func TestMapper_Patch_Map(t *testing.T) {
+	t.Parallel()
+	patch := map[string]interface{}{
+		"display_name": "name",
+		"email":        nil,
+		"nested":       map[string]interface{}{"string": "string"},
+	}
+	to := Patch2{DisplayName: "old", Email: "email", Nested: &Simple2{Int: 1}}
+
+	m := automapper.New()
+	err := m.Patch(patch, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Patch2{DisplayName: "name", Nested: &Simple2{Int: 1, String: "string"}}, to)
+}
+
+func TestMapper_Patch_Map_MissingConverter(t *testing.T) {
+	t.Parallel()
+	patch := map[string]interface{}{"email": 1.0}
+	to := Patch2{}
+
+	m := automapper.New()
+	err := m.Patch(patch, &to)
+
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+}
+
+type JSONPatchTarget struct {
+	Count  int
+	Ratio  float32
+	Tags   []string
+	Limits map[string]uint8
+	Lines  []Simple2
+	Owner  *Simple2
+}
+
+func TestMapper_Patch_JSON(t *testing.T) {
+	t.Parallel()
+	var patch map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"count": 3,
+		"ratio": 0.5,
+		"tags": ["a", "b"],
+		"limits": {"daily": 10},
+		"lines": [{"int": 1, "string": "x"}, null],
+		"owner": {"int": 2}
+	}`), &patch))
+	to := JSONPatchTarget{Count: 1, Tags: []string{"old"}}
+
+	m := automapper.New()
+	err := m.Patch(patch, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, JSONPatchTarget{
+		Count:  3,
+		Ratio:  0.5,
+		Tags:   []string{"a", "b"},
+		Limits: map[string]uint8{"daily": 10},
+		Lines:  []Simple2{{Int: 1, String: "x"}, {}},
+		Owner:  &Simple2{Int: 2},
+	}, to)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"limits": {"daily": 300}}`), &patch))
+	err = m.Patch(patch, &to)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, automapper.CodeOverflow, mapErr.Code)
+	assert.Equal(t, "Limits[daily]", mapErr.Path)
+}
+
+func TestMapper_Diff(t *testing.T) {
+	t.Parallel()
+	from := Masked1{
+		DisplayName: "name",
+		Email:       "email",
+		Nested:      Simple1{Int: 2},
+		Items:       []Simple1{{Int: 1, String: "new"}},
+	}
+	to := Masked2{
+		DisplayName: "name",
+		Email:       "old",
+		Nested:      Simple2{Int: 1},
+		Items:       []Simple2{{Int: 1, String: "old"}},
+	}
+
+	m := automapper.New()
+	diff, err := m.Diff(&from, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Email", "Nested.Int", "Items[0].String"}, diff)
+	assert.Equal(t, "old", to.Email)
+	assert.Equal(t, "old", to.Items[0].String)
+
+	_, err = m.Diff(&from, to)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.ErrorIs(t, err, automapper.ErrNotAPtr)
+	assert.Equal(t, automapper.CodeUnsupportedType, mapErr.Code)
+}
+
+type HeldSource struct {
+	Held Simple1
+}
+
+type HeldDestination struct {
+	Held interface{}
+}
+
+func TestMapper_Diff_HeldPointer(t *testing.T) {
+	t.Parallel()
+	held := &Simple2{Int: 1, String: "old"}
+	to := HeldDestination{Held: held}
+
+	m := automapper.New()
+	diff, err := m.Diff(&HeldSource{Held: Simple1{Int: 1, String: "new"}}, &to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Held"}, diff)
+	assert.Same(t, held, to.Held)
+	assert.Equal(t, Simple2{Int: 1, String: "old"}, *held)
+}
+
+func TestMapper_Map_Only(t *testing.T) {
+	t.Parallel()
+	from := Masked1{DisplayName: "name", Email: "email", Nested: Simple1{Int: 1, String: "string"}}
+	to := Masked2{}
+
+	m := automapper.New()
+	err := m.Map(&from, &to, automapper.Only("DisplayName", "Nested.String"))
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Masked2{DisplayName: "name", Nested: Simple2{String: "string"}}, to)
+}
+
+func TestMapper_Map_Except(t *testing.T) {
+	t.Parallel()
+	from := Masked1{DisplayName: "name", Email: "email", Nested: Simple1{Int: 1, String: "string"}}
+	to := Masked2{}
+
+	m := automapper.New()
+	err := m.Map(&from, &to, automapper.Except("Email", "Nested.String"))
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Masked2{DisplayName: "name", Nested: Simple2{Int: 1}}, to)
+}
+
+type MaskedAddress struct {
+	City string
+	Zip  string
+}
+
+type MaskedPerson struct {
+	Name string
+	Addr MaskedAddress
+	Home *MaskedAddress
+	Past []MaskedAddress
+}
+
+type MaskedPersonDTO struct {
+	Name string
+	Addr MaskedAddress
+	Home *MaskedAddress
+	Past []MaskedAddress
+}
+
+func TestMapper_Map_Only_SameNestedTypes(t *testing.T) {
+	t.Parallel()
+	from := MaskedPerson{
+		Name: "name",
+		Addr: MaskedAddress{City: "city", Zip: "zip"},
+		Home: &MaskedAddress{City: "home", Zip: "home zip"},
+		Past: []MaskedAddress{{City: "past", Zip: "past zip"}},
+	}
+
+	m := automapper.New()
+	to := MaskedPersonDTO{}
+	err := m.Map(&from, &to, automapper.Only("Addr.City", "Home.City", "Past.City"))
+	assert.NoError(t, err)
+	assert.Equal(t, MaskedPersonDTO{
+		Addr: MaskedAddress{City: "city"},
+		Home: &MaskedAddress{City: "home"},
+		Past: []MaskedAddress{{City: "past"}},
+	}, to)
+	assert.NotSame(t, from.Home, to.Home)
+
+	to = MaskedPersonDTO{}
+	err = m.Map(&from, &to, automapper.Except("Addr.City", "Home.Zip"))
+	assert.NoError(t, err)
+	assert.Equal(t, MaskedPersonDTO{
+		Name: "name",
+		Addr: MaskedAddress{Zip: "zip"},
+		Home: &MaskedAddress{City: "home"},
+		Past: []MaskedAddress{{City: "past", Zip: "past zip"}},
+	}, to)
+
+	to = MaskedPersonDTO{}
+	err = m.MapMasked(&from, &to, []string{"name", "addr.zip"})
+	assert.NoError(t, err)
+	assert.Equal(t, MaskedPersonDTO{Name: "name", Addr: MaskedAddress{Zip: "zip"}}, to)
+}
+
+type isPbEventKind interface {
+	isPbEventKind()
+}
+
+type PbEventCreated struct {
+	Created *Simple1
+}
+
+func (*PbEventCreated) isPbEventKind() {}
+
+// PbEvent mimics protoc-gen-go output.
+type PbEvent struct {
+	state         int
+	sizeCache     int32
+	unknownFields []byte
+
+	ID   string
+	Name *string
+	Kind isPbEventKind
+}
+
+func (x *PbEvent) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *PbEvent) GetCreated() *Simple1 {
+	if x, ok := x.Kind.(*PbEventCreated); ok {
+		return x.Created
+	}
+	return nil
+}
+
+type Event struct {
+	ID      string
+	Name    string
+	Created *Simple2
+}
+
+func TestMapper_Map_Protobuf_From(t *testing.T) {
+	t.Parallel()
+	name := "name"
+	from := PbEvent{ID: "id", Name: &name, Kind: &PbEventCreated{Created: &Simple1{Int: 1}}}
+	to := Event{}
+
+	m := automapper.New(automapper.WithProtobuf())
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Event{ID: "id", Name: "name", Created: &Simple2{Int: 1}}, to)
+}
+
+func TestMapper_Map_Protobuf_To(t *testing.T) {
+	t.Parallel()
+	from := Event{ID: "id", Created: &Simple2{Int: 1}}
+	to := PbEvent{}
+
+	m := automapper.New(automapper.WithProtobuf())
+	m.RegisterImplementations(&PbEventCreated{})
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, "id", to.ID)
+	assert.EqualValues(t, &PbEventCreated{Created: &Simple1{Int: 1}}, to.Kind)
+}
+
+func TestMapper_CreateMap_Protobuf(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithProtobuf())
+	m.RegisterImplementations(&PbEventCreated{})
+
+	err := m.CreateMap(Event{}, PbEvent{})
+
+	assert.NoError(t, err)
+}
+
+type PbEventDeleted struct {
+	Deleted *Simple2
+}
+
+func (*PbEventDeleted) isPbEventKind() {}
+
+type DomainEvent interface {
+	isDomainEvent()
+}
+
+type CreatedEvent struct {
+	Int int
+}
+
+func (CreatedEvent) isDomainEvent() {}
+
+type DeletedEvent struct {
+	String string
+}
+
+func (*DeletedEvent) isDomainEvent() {}
+
+type SumEvent struct {
+	ID   string
+	Kind DomainEvent
+}
+
+func TestMapper_RegisterVariant(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithProtobuf())
+	assert.NoError(t, m.RegisterVariant(&PbEventCreated{}, CreatedEvent{}))
+	assert.NoError(t, m.RegisterVariant(&PbEventDeleted{}, &DeletedEvent{}))
+
+	to := SumEvent{}
+	err := m.Map(&PbEvent{ID: "1", Kind: &PbEventCreated{Created: &Simple1{Int: 1}}}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, SumEvent{ID: "1", Kind: CreatedEvent{Int: 1}}, to)
+
+	to = SumEvent{}
+	err = m.Map(&PbEvent{ID: "2", Kind: &PbEventDeleted{Deleted: &Simple2{String: "spam"}}}, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, SumEvent{ID: "2", Kind: &DeletedEvent{String: "spam"}}, to)
+
+	pb := PbEvent{}
+	err = m.Map(&SumEvent{ID: "3", Kind: &DeletedEvent{String: "spam"}}, &pb)
+	assert.NoError(t, err)
+	assert.Equal(t, &PbEventDeleted{Deleted: &Simple2{String: "spam"}}, pb.Kind)
+
+	err = m.Map(&SumEvent{ID: "3", Kind: CreatedEvent{Int: 2}}, &pb)
+	assert.NoError(t, err)
+	assert.Equal(t, &PbEventCreated{Created: &Simple1{Int: 2}}, pb.Kind)
+
+	err = m.RegisterVariant(PbEventCreated{}, CreatedEvent{})
+	assert.ErrorIs(t, err, automapper.ErrNotAOneofWrapper)
+}
+
+type UserInput struct {
+	Name     *string   `graphql:"name"`
+	Nickname *string   `graphql:"nickname"`
+	Tags     *[]string `graphql:"tags"`
+	Age      *int      `graphql:"age"`
+}
+
+type UserModel struct {
+	FullName string   `graphql:"name"`
+	Nickname string   `graphql:"nickname"`
+	Labels   []string `graphql:"tags"`
+	Age      int      `graphql:"age"`
+}
+
+func TestMapper_Map_GraphQL(t *testing.T) {
+	t.Parallel()
+	name := "name"
+	tags := []string{"a"}
+	model := UserModel{FullName: "old", Nickname: "nick", Age: 1}
+
+	m := automapper.New(automapper.WithGraphQL(automapper.NilSkip))
+	err := m.Map(&UserInput{Name: &name, Tags: &tags}, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, UserModel{FullName: "name", Nickname: "nick", Labels: []string{"a"}, Age: 1}, model)
+
+	input := UserInput{}
+	err = m.Map(&UserModel{FullName: "name"}, &input)
+	assert.NoError(t, err)
+	assert.Equal(t, UserInput{Name: &name}, input)
+
+	m = automapper.New(automapper.WithGraphQL(automapper.NilZero))
+	err = m.Map(&UserInput{Name: &name}, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, UserModel{FullName: "name"}, model)
+
+	empty := ""
+	zero := 0
+	input = UserInput{}
+	err = m.Map(&UserModel{FullName: "name"}, &input)
+	assert.NoError(t, err)
+	assert.Equal(t, UserInput{Name: &name, Nickname: &empty, Tags: new([]string), Age: &zero}, input)
+
+	// graphql tags aren't used without the option
+	err = automapper.New().Map(&UserInput{Name: &name}, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, UserModel{FullName: "name"}, model)
+}
+
+// NullableString mimics openapi-generator output.
+type NullableString struct {
+	value *string
+	isSet bool
+}
+
+func (v NullableString) Get() *string { return v.value }
+
+func (v *NullableString) Set(val *string) {
+	v.value = val
+	v.isSet = true
+}
+
+func (v NullableString) IsSet() bool { return v.isSet }
+
+// PetModel mimics oapi-codegen output.
+type PetModel struct {
+	Name                 string                 `json:"name"`
+	Nickname             NullableString         `json:"nickname"`
+	Age                  *int                   `json:"age,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+type Pet struct {
+	Name     string
+	Nickname string
+	Age      int
+	Color    string `json:"color"`
+	Weight   float64
+}
+
+func TestMapper_Map_OpenAPI(t *testing.T) {
+	t.Parallel()
+	m := automapper.New(automapper.WithOpenAPI(automapper.NilSkip))
+	nickname := "rex"
+	age := 3
+	pet := Pet{Nickname: "old", Weight: 1}
+	err := m.Map(&PetModel{
+		Name:                 "dog",
+		Nickname:             NullableString{value: &nickname, isSet: true},
+		Age:                  &age,
+		AdditionalProperties: map[string]interface{}{"color": "brown", "unknown": 1},
+	}, &pet)
+	assert.NoError(t, err)
+	assert.Equal(t, Pet{Name: "dog", Nickname: "rex", Age: 3, Color: "brown", Weight: 1}, pet)
+
+	// unset nullable is skipped, explicit null clears destination
+	err = m.Map(&PetModel{}, &pet)
+	assert.NoError(t, err)
+	assert.Equal(t, "rex", pet.Nickname)
+	err = m.Map(&PetModel{Nickname: NullableString{isSet: true}}, &pet)
+	assert.NoError(t, err)
+	assert.Empty(t, pet.Nickname)
+
+	model := PetModel{}
+	err = m.Map(&Pet{Name: "cat", Nickname: "tom", Color: "black", Weight: 2.5}, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, PetModel{
+		Name:                 "cat",
+		Nickname:             NullableString{value: &[]string{"tom"}[0], isSet: true},
+		AdditionalProperties: map[string]interface{}{"color": "black", "Weight": 2.5},
+	}, model)
+}
+
+type BaseEntity struct {
+	ID        int
+	CreatedAt time.Time
+}
+
+type Product struct {
+	BaseEntity
+	Name string
+}
+
+type AuditedProduct struct {
+	*BaseEntity
+	Name string
+}
+
+type ProductDTO struct {
+	ID        int
+	CreatedAt time.Time
+	Name      string
+}
+
+func TestMapper_Flatten(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	m.Flatten(BaseEntity{})
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dto := ProductDTO{}
+	err := m.Map(&Product{BaseEntity: BaseEntity{ID: 1, CreatedAt: created}, Name: "name"}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, ProductDTO{ID: 1, CreatedAt: created, Name: "name"}, dto)
+
+	product := Product{}
+	err = m.Map(&dto, &product)
+	assert.NoError(t, err)
+	assert.Equal(t, Product{BaseEntity: BaseEntity{ID: 1, CreatedAt: created}, Name: "name"}, product)
+
+	audited := AuditedProduct{}
+	err = m.Map(&dto, &audited)
+	assert.NoError(t, err)
+	assert.Equal(t, AuditedProduct{BaseEntity: &BaseEntity{ID: 1, CreatedAt: created}, Name: "name"}, audited)
+
+	dto = ProductDTO{}
+	err = m.Map(&AuditedProduct{Name: "name"}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, ProductDTO{Name: "name"}, dto)
+
+	// embedded structs are mapped as a whole if both sides have them
+	product = Product{}
+	err = m.Map(&AuditedProduct{BaseEntity: &BaseEntity{ID: 2}}, &product)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, product.ID)
+
+	var plans bytes.Buffer
+	assert.NoError(t, m.ExportPlans(&plans))
+	restored := automapper.New()
+	restored.Flatten(BaseEntity{})
+	assert.NoError(t, restored.ImportPlans(&plans))
+	dto = ProductDTO{}
+	err = restored.Map(&Product{BaseEntity: BaseEntity{ID: 3}}, &dto)
+	assert.NoError(t, err)
+	assert.Equal(t, ProductDTO{ID: 3}, dto)
+	product = Product{}
+	err = restored.Map(&ProductDTO{ID: 4}, &product)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, product.ID)
+}
+
+// UnionNullString mimics gogen-avro output.
+type UnionNullString struct {
+	String    string
+	UnionType int
+}
+
+func (u *UnionNullString) UnionValue() interface{} {
+	if u.UnionType == 0 {
+		return nil
+	}
+
+	return u.String
+}
+
+func (u *UnionNullString) SetUnionValue(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("unexpected union member %T", value)
+	}
+
+	u.String, u.UnionType = s, 1
+	return nil
+}
+
+type KafkaPayload struct {
+	Name  *UnionNullString
+	Email UnionNullString
+}
+
+type Contact struct {
+	Name  string
+	Email string
+}
+
+func TestMapper_Map_Unions(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+
+	contact := Contact{Email: "old"}
+	err := m.Map(&KafkaPayload{Name: &UnionNullString{String: "name", UnionType: 1}, Email: UnionNullString{String: "ignored"}}, &contact)
+	assert.NoError(t, err)
+	assert.Equal(t, Contact{Name: "name", Email: "old"}, contact)
+
+	payload := KafkaPayload{}
+	err = m.Map(&Contact{Name: "name", Email: "email"}, &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, KafkaPayload{
+		Name:  &UnionNullString{String: "name", UnionType: 1},
+		Email: UnionNullString{String: "email", UnionType: 1},
+	}, payload)
+
+	err = m.Map(&struct{ Email int }{1}, &payload)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+type Form struct {
+	Name     string
+	PageSize int `mapper:"page_size"`
+	IDs      []int
+	Enabled  *bool
+}
+
+func TestMapper_MapURLValues(t *testing.T) {
+	t.Parallel()
+	values := url.Values{
+		"name":      {"name"},
+		"page_size": {"10"},
+		"ids":       {"1", "2"},
+		"enabled":   {"true"},
+		"unknown":   {"value"},
+	}
+	to := Form{}
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Atoi))
+	assert.NoError(t, m.Set(strconv.ParseBool))
+
+	err := m.MapURLValues(values, &to)
+
+	assert.NoError(t, err)
+	enabled := true
+	assert.EqualValues(t, Form{Name: "name", PageSize: 10, IDs: []int{1, 2}, Enabled: &enabled}, to)
+}
+
+func TestMapper_MapURLValues_MissingConverter(t *testing.T) {
+	t.Parallel()
+	to := Simple2{}
+	m := automapper.New()
+
+	err := m.MapURLValues(url.Values{"time": {"2020-01-01"}}, &to)
+
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Time", mapErr.Path)
+}
+
+func TestMapper_MapURLValues_SameAsStringMap(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	values := url.Values{"page_size": {"10"}, "ids": {"1", "2"}, "enabled": {"true"}}
+
+	var form, mapped Form
+	err := m.MapURLValues(values, &form)
+	assert.NoError(t, err)
+	err = m.Map(values, &mapped)
+	assert.NoError(t, err)
+	enabled := true
+	assert.Equal(t, Form{PageSize: 10, IDs: []int{1, 2}, Enabled: &enabled}, form)
+	assert.Equal(t, form, mapped)
+
+	invalid := url.Values{"page_size": {"x"}}
+	err = m.MapURLValues(invalid, &form)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	err = m.Map(invalid, &mapped)
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+}
+
+type Query struct {
+	Name     string
+	PageSize int `mapper:"page_size"`
+	Offset   int `mapper:"offset,omitempty"`
+	IDs      []int
+	Enabled  *bool
+}
+
+func TestMapper_URLValues(t *testing.T) {
+	t.Parallel()
+	from := Query{Name: "name", IDs: []int{1, 2}}
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+
+	values, err := m.URLValues(&from)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, url.Values{
+		"Name":      {"name"},
+		"page_size": {"0"},
+		"IDs":       {"1", "2"},
+	}, values)
+}
+
+type Env struct {
+	Host    string
+	Port    uint16
+	Debug   bool
+	Timeout time.Duration
+	Ratio   *float64
+}
+
+func TestMapper_Map_StringMap(t *testing.T) {
+	t.Parallel()
+	from := map[string]string{
+		"HOST":    "localhost",
+		"PORT":    "8080",
+		"DEBUG":   "true",
+		"TIMEOUT": "1s",
+		"RATIO":   "0.5",
+	}
+	to := Env{}
+
+	m := automapper.New()
+	err := m.Map(from, &to)
+
+	assert.NoError(t, err)
+	ratio := 0.5
+	assert.EqualValues(t, Env{Host: "localhost", Port: 8080, Debug: true, Timeout: time.Second, Ratio: &ratio}, to)
+}
+
+func TestMapper_Map_StringMap_Headers(t *testing.T) {
+	t.Parallel()
+	from := http.Header{"Host": {"localhost"}, "Port": {"8080"}}
+	to := Env{}
+
+	m := automapper.New()
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Env{Host: "localhost", Port: 8080}, to)
+}
+
+func TestMapper_Map_StringMap_ParseError(t *testing.T) {
+	t.Parallel()
+	to := Env{}
+
+	m := automapper.New()
+	err := m.Map(map[string]string{"PORT": "100000"}, &to)
+
+	assert.ErrorIs(t, err, automapper.ErrConverter)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Port", mapErr.Path)
+}
+
+type Row struct {
+	Name  string `mapper:"name,csv=0"`
+	Count int    `mapper:"csv=2"`
+	Skip  string
+}
+
+func TestMapper_Map_FromRecord(t *testing.T) {
+	t.Parallel()
+	from := []string{"name", "ignored", "10"}
+	to := Row{}
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Atoi))
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Row{Name: "name", Count: 10}, to)
+
+	err = automapper.New().Map(&from, &Row{})
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Count", mapErr.Path)
+}
+
+func TestMapper_Map_ToRecord(t *testing.T) {
+	t.Parallel()
+	from := Row{Name: "name", Count: 10, Skip: "skip"}
+	var to []string
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"name", "", "10"}, to)
+
+	err = automapper.New().Map(&from, &to)
+	var mapErr *automapper.Error
+	assert.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, "Count", mapErr.Path)
+}
+
+func TestMapper_Map_IdenticalTypes(t *testing.T) {
+	t.Parallel()
+	simple := Simple1{Int: 1, String: "string"}
+	from := Structs1{Field1: simple, Field3: &simple}
+	to := Structs1{}
+
+	m := automapper.New()
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, from, to)
+}
+
+func TestMapper_Map_IdenticalTypes_NonZeroDestination(t *testing.T) {
+	t.Parallel()
+	from := Simple1{Int: 1}
+	to := Simple1{String: "string"}
+
+	m := automapper.New()
+	err := m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Simple1{Int: 1, String: "string"}, to)
+}
+
+func TestMapper_Map_Pooling(t *testing.T) {
+	t.Parallel()
+	for _, enabled := range []bool{true, false} {
+		from := Masked1{DisplayName: "name", Email: "email"}
+		m := automapper.New(automapper.WithPooling(enabled))
+
+		masked := Masked2{}
+		err := m.Map(&from, &masked, automapper.Only("Email"))
+		assert.NoError(t, err)
+		to := Masked2{}
+		err = m.Map(&from, &to)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, Masked2{Email: "email"}, masked)
+		assert.EqualValues(t, Masked2{DisplayName: "name", Email: "email"}, to)
+	}
+}
+
+func TestMapper_Map_Reuse(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	first := Structs2{}
+	err := m.Map(&Structs1{Field1: Simple1{Int: 1}}, &first)
+	assert.NoError(t, err)
+
+	from := Structs1{Field2: Simple1{String: "string"}, Field4: &Simple1{Int: 2}}
+	to := Structs2{}
+	err = m.Map(&from, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, Structs2{Field2: &Simple2{String: "string"}, Field4: &Simple2{Int: 2}}, to)
+}
+
+func TestMapper_Map_ConverterAfterMap(t *testing.T) {
+	t.Parallel()
+	m := automapper.New()
+	to := Converters2{}
+	err := m.Map(&Converters1{1}, &to)
+	assert.ErrorIs(t, err, automapper.ErrMissingConverter)
+
+	assert.NoError(t, m.Set(strconv.Itoa))
+	err = m.Map(&Converters1{1}, &to)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, "1", to.Field1)
+}
+
+func TestMapper_ExportPlans(t *testing.T) {
+	t.Parallel()
+	exporter := automapper.New()
+	assert.NoError(t, exporter.Set(strconv.Itoa))
+	err := exporter.Map(&Structs1{Field1: Simple1{Int: 1}}, &Structs2{})
+	assert.NoError(t, err)
+	err = exporter.Map(&Converters1{1}, &Converters2{})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = exporter.ExportPlans(&buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"strategy":"converterFunc","converter":"strconv.Itoa"`)
+
+	m := automapper.New()
+	assert.NoError(t, m.Set(strconv.Itoa))
+	err = m.ImportPlans(&buf)
+	assert.NoError(t, err)
+	from := Structs1{Field2: Simple1{Int: 1}}
+	to := Structs2{}
+	err = m.Map(&from, &to)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Simple2{Int: 1}, to.Field2)
+	converted := Converters2{}
+	err = m.Map(&Converters1{1}, &converted)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "1", converted.Field1)
+}
+
+type ExportedContact struct {
+	Email string
+	Age   int
+	Score int
+}
+
+type ExportedContactView struct {
+	EmailDTO string
+	Age      int
+}
+
+func TestMapper_ImportPlans_ConfigurationChanged(t *testing.T) {
+	t.Parallel()
+	contact := ExportedContact{Email: "a@example.com", Age: 1, Score: 2}
+	exporter := automapper.New(automapper.StripDestSuffix("DTO"))
+	var view ExportedContactView
+	assert.NoError(t, exporter.Map(&contact, &view))
+	assert.Equal(t, ExportedContactView{EmailDTO: "a@example.com", Age: 1}, view)
+	var buf bytes.Buffer
+	assert.NoError(t, exporter.ExportPlans(&buf))
+	// restored plans are told from rebuilt ones by Age mapped from Score
+	exported := strings.Replace(buf.String(), `"name":"Age","fromIndex":1`, `"name":"Age","fromIndex":2`, 1)
+
+	m := automapper.New(automapper.StripDestSuffix("DTO"))
+	assert.NoError(t, m.ImportPlans(strings.NewReader(exported)))
+	view = ExportedContactView{}
+	assert.NoError(t, m.Map(&contact, &view))
+	assert.Equal(t, ExportedContactView{EmailDTO: "a@example.com", Age: 2}, view)
+
+	for name, m := range map[string]*automapper.Mapper{
+		"name transformers": automapper.New(),
+		"precedence":        automapper.New(automapper.StripDestSuffix("DTO")),
+		"clone method":      automapper.New(automapper.StripDestSuffix("DTO")),
+	} {
+		switch name {
+		case "precedence":
+			assert.NoError(t, m.SetPrecedence(automapper.StrategySameTypes))
+		case "clone method":
+			m.SetCloneMethod("")
+		}
+
+		assert.NoError(t, m.ImportPlans(strings.NewReader(exported)))
+		view = ExportedContactView{}
+		assert.NoError(t, m.Map(&contact, &view))
+		assert.Equal(t, 1, view.Age, name)
+	}
+}
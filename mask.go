@@ -0,0 +1,123 @@
+package automapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldSelector restricts the set of fields mapped by a call.
+// A nil selector selects every field.
+type fieldSelector struct {
+	// fields holds listed field names. A nil value lists the whole field,
+	// a non-nil value restricts the nested field with its own selector.
+	fields map[string]*fieldSelector
+	// except inverts selector: listed fields are excluded instead of being the only ones mapped.
+	except bool
+}
+
+// newFieldSelector builds selector from dot-separated paths, e.g. "user.display_name".
+// Returns nil if no paths are given.
+func newFieldSelector(paths []string, except bool) *fieldSelector {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	root := &fieldSelector{fields: make(map[string]*fieldSelector), except: except}
+	for _, path := range paths {
+		node := root
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			name := normalizeFieldName(part)
+			child, ok := node.fields[name]
+			if ok && child == nil {
+				// whole field is already listed
+				break
+			}
+
+			if i == len(parts)-1 {
+				node.fields[name] = nil
+				break
+			}
+
+			if !ok {
+				child = &fieldSelector{fields: make(map[string]*fieldSelector), except: except}
+				node.fields[name] = child
+			}
+
+			node = child
+		}
+	}
+
+	return root
+}
+
+// child reports whether field is selected and returns selector for its nested fields.
+func (s *fieldSelector) child(name string) (*fieldSelector, bool) {
+	if s == nil {
+		return nil, true
+	}
+
+	child, ok := s.fields[normalizeFieldName(name)]
+	if s.except {
+		// unlisted fields are mapped entirely, listed ones are excluded unless restricted further
+		return child, !ok || child != nil
+	}
+
+	return child, ok
+}
+
+// normalizeFieldName makes Go field names and FieldMask paths comparable:
+// "DisplayName" and "display_name" are the same field.
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// hasSelectableFields reports whether tp is a struct, which fields are all exported, a pointer to it,
+// or a slice or array of them, so that selectors of the call can be applied to its fields.
+// Structs with unexported fields, e.g. time.Time, are copied as a whole to keep unexported values.
+func hasSelectableFields(tp reflect.Type) bool {
+	for tp.Kind() == reflect.Ptr || tp.Kind() == reflect.Slice || tp.Kind() == reflect.Array {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct {
+		return false
+	}
+
+	for _, meta := range cachedFields(tp).list {
+		if !meta.exported {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldwise reports whether values of identical type tp are mapped field by field: selectors
+// of the call apply to fields of nested structs, and nested structs of tracked or skipped writes
+// are compared field by field, see MapTrack and WithSkipEqualWrites.
+func (s *mapState) fieldwise(tp reflect.Type) bool {
+	if len(s.sels) > 0 {
+		return hasSelectableFields(tp)
+	}
+
+	return (s.changes != nil || s.opts.skipEqual) && tp.Kind() == reflect.Struct && hasSelectableFields(tp)
+}
+
+// mapSelected maps values of identical types field by field, so that selectors of the call apply
+// to fields of nested structs, see Only and Except.
+func (m *Mapper) mapSelected(st *mapState, fromVal, toVal reflect.Value) error {
+	switch {
+	case (fromVal.Kind() == reflect.Ptr || fromVal.Kind() == reflect.Slice) && fromVal.IsNil():
+		toVal.Set(fromVal)
+		return nil
+	case fromVal.Kind() == reflect.Ptr && fromVal.Elem().Kind() != reflect.Struct:
+		return m.mapPointersFunc(st, fromVal, toVal)
+	case fromVal.Kind() == reflect.Slice:
+		return m.mapSlicesFunc(st, fromVal, toVal)
+	case fromVal.Kind() == reflect.Array:
+		return m.mapArraysFunc(st, fromVal, toVal)
+	default:
+		return m.mapStructsFunc(st, fromVal, toVal)
+	}
+}
@@ -0,0 +1,186 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MoneyCodec converts values of money type M, e.g. google.type.Money, into amounts
+// in minor units with currency codes and back, see RegisterMoney.
+type MoneyCodec[M any] struct {
+	// Split returns amount in minor units, e.g. cents, and ISO 4217 currency code of money.
+	Split func(money M) (amount int64, currency string)
+	// Join returns money of amount in minor units and currency code.
+	Join func(amount int64, currency string) (M, error)
+}
+
+// RegisterMoney registers codec of money type M, which fields are mapped with it instead of field by field:
+//   - field Price of type M or *M maps from and into PriceAmount integer and PriceCurrency string fields;
+//   - fields of different registered money types map into each other through amounts and currencies.
+//
+// Join errors are wrapped with ErrConverter.
+func RegisterMoney[M any](m *Mapper, codec MoneyCodec[M]) error {
+	if codec.Split == nil || codec.Join == nil {
+		return fmt.Errorf("%w: money codec of '%s'", ErrNilValue, reflect.TypeOf((*M)(nil)).Elem())
+	}
+
+	if m.moneys == nil {
+		m.moneys = make(map[reflect.Type]moneyCodec)
+	}
+
+	m.moneys[reflect.TypeOf((*M)(nil)).Elem()] = moneyCodec{
+		split: func(money reflect.Value) (int64, string) {
+			value, _ := money.Interface().(M)
+			return codec.Split(value)
+		},
+		join: func(amount int64, currency string) (reflect.Value, error) {
+			money, err := codec.Join(amount, currency)
+			return reflect.ValueOf(&money).Elem(), err
+		},
+	}
+	m.resetPlans()
+	return nil
+}
+
+// Suffixes of names of fields holding parts of money.
+const (
+	amountSuffix   = "Amount"
+	currencySuffix = "Currency"
+)
+
+// moneyCodec is MoneyCodec working with reflect values.
+type moneyCodec struct {
+	split func(money reflect.Value) (int64, string)
+	join  func(amount int64, currency string) (reflect.Value, error)
+}
+
+// moneyPair is amount and currency source fields of destination money field of op.
+type moneyPair struct {
+	codec    moneyCodec
+	currency int
+}
+
+// moneyType returns codec of money type of tp or pointer to it.
+func (m *Mapper) moneyType(tp reflect.Type) (moneyCodec, bool) {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	codec, ok := m.moneys[tp]
+	return codec, ok
+}
+
+// bindMoney binds conversion between different money types of op, reporting whether they are money types.
+func (m *Mapper) bindMoney(op *fieldOp) bool {
+	fromCodec, fromMoney := m.moneyType(op.fromType)
+	toCodec, toMoney := m.moneyType(op.toType)
+	if !fromMoney || !toMoney || op.fromType == op.toType {
+		return false
+	}
+
+	op.strategy = converterFunc
+	op.mapperFunc = func(_ *mapState, fromVal, toVal reflect.Value) error {
+		amount, currency := fromCodec.split(reflect.Indirect(fromVal))
+		return setMoney(toCodec, toVal, amount, currency)
+	}
+
+	return true
+}
+
+// moneyFieldOp returns op mapping money parts into money field with name, or part of money
+// into field with name, when source has no field with name, see RegisterMoney.
+func (m *Mapper) moneyFieldOp(mappingInfo structMappingInfo, fromFields *structFields, name string, toType reflect.Type) (fieldOp, bool) {
+	if len(m.moneys) == 0 || mappingInfo.protobuf {
+		return fieldOp{}, false
+	}
+
+	op := fieldOp{name: name, getter: -1, toType: toType, strategy: converterFunc}
+	codec, ok := m.moneyType(toType)
+	if !ok {
+		return m.moneyPartOp(mappingInfo, fromFields, op)
+	}
+
+	amount, okAmount := fromFields.publicField(name + amountSuffix)
+	currency, okCurrency := fromFields.publicField(name + currencySuffix)
+	if !okAmount || !okCurrency || !isIntKind(mappingInfo.from.Field(amount.index).Type.Kind()) ||
+		mappingInfo.from.Field(currency.index).Type.Kind() != reflect.String {
+		return fieldOp{}, false
+	}
+
+	op.fromIndex, op.fromType = amount.index, mappingInfo.from.Field(amount.index).Type
+	op.money = &moneyPair{codec: codec, currency: currency.index}
+	return op, true
+}
+
+// moneyPartOp returns op mapping amount or currency of source money field into field of op,
+// e.g. Price into PriceAmount.
+func (m *Mapper) moneyPartOp(mappingInfo structMappingInfo, fromFields *structFields, op fieldOp) (fieldOp, bool) {
+	isAmount := strings.HasSuffix(op.name, amountSuffix) && isIntKind(op.toType.Kind())
+	isCurrency := strings.HasSuffix(op.name, currencySuffix) && op.toType.Kind() == reflect.String
+	if !isAmount && !isCurrency {
+		return fieldOp{}, false
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(op.name, amountSuffix), currencySuffix)
+	meta, ok := fromFields.publicField(base)
+	if !ok {
+		return fieldOp{}, false
+	}
+
+	op.fromIndex, op.fromType = meta.index, mappingInfo.from.Field(meta.index).Type
+	codec, ok := m.moneyType(op.fromType)
+	if !ok {
+		return fieldOp{}, false
+	}
+
+	op.mapperFunc = func(_ *mapState, fromVal, toVal reflect.Value) error {
+		amount, currency := codec.split(reflect.Indirect(fromVal))
+		if isAmount {
+			toVal.SetInt(amount)
+		} else {
+			toVal.SetString(currency)
+		}
+
+		return nil
+	}
+
+	return op, true
+}
+
+// mapMoneyPair joins amount and currency source fields of op into money to.
+func mapMoneyPair(op *fieldOp, from, to reflect.Value) error {
+	amount, currency := op.source(from), from.Field(op.money.currency)
+	if amount.IsZero() && currency.IsZero() {
+		return nil
+	}
+
+	return setMoney(op.money.codec, to, amount.Int(), currency.String())
+}
+
+// setMoney sets money joined by codec into money or pointer to money to.
+func setMoney(codec moneyCodec, to reflect.Value, amount int64, currency string) error {
+	money, err := codec.join(amount, currency)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConverter, err)
+	}
+
+	if to.Kind() == reflect.Ptr {
+		ptr := reflect.New(to.Type().Elem())
+		ptr.Elem().Set(money)
+		to.Set(ptr)
+		return nil
+	}
+
+	to.Set(money)
+	return nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
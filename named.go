@@ -0,0 +1,58 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SetNamed registers converter under name, which fields select with `mapper:",converter=name"`
+// tag option of source or destination field instead of converters registered with Set,
+// e.g. to map some of string fields between different casing conventions.
+// Converter has the same form as converters of Set and must accept and return field types.
+func (m *Mapper) SetNamed(name string, converter interface{}) error {
+	fn := reflect.TypeOf(converter)
+	if err := validateConverter(fn); err != nil {
+		return err
+	}
+
+	if m.named == nil {
+		m.named = make(map[string]reflect.Value)
+	}
+
+	m.named[name] = reflect.ValueOf(converter)
+	m.resetPlans()
+	return nil
+}
+
+// bindNamed binds converter registered with name to op, or sets tag error if it's missing
+// or can't map op types.
+func (m *Mapper) bindNamed(op *fieldOp, name string) {
+	converter, ok := m.named[name]
+	if !ok {
+		op.tagErr = fmt.Errorf("%w: unknown converter '%s' of field %s", ErrInvalidTag, name, op.name)
+		return
+	}
+
+	fn := converter.Type()
+	if !op.fromType.AssignableTo(fn.In(0)) || !fn.Out(0).AssignableTo(op.toType) {
+		op.tagErr = fmt.Errorf("%w: converter '%s' of field %s can't map '%s -> %s'",
+			ErrInvalidTag, name, op.name, op.fromType, op.toType)
+		return
+	}
+
+	op.strategy, op.converter, op.named = converterFunc, converter, true
+	op.mapperFunc = func(st *mapState, fromVal, toVal reflect.Value) error {
+		return m.callConverter(converter, fromVal, toVal)
+	}
+}
+
+// usesNamed reports whether any field selects named converter, see SetNamed.
+func (f *structFields) usesNamed() bool {
+	for i := range f.list {
+		if f.list[i].hasOption(tagConverter) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package automapper
+
+import "strings"
+
+// WithSourceNameTransformer adds transformer of source field names, which are matched
+// with destination field names after transforming, e.g. strings.ToLower.
+// Transformers are applied in the order they are added. The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func WithSourceNameTransformer(transform func(name string) string) Option {
+	return func(o *options) {
+		o.sourceNames = append(o.sourceNames, transform)
+	}
+}
+
+// WithDestNameTransformer adds transformer of destination field names, e.g. one stripping "Dto" suffixes,
+// so that source and destination types may follow different naming schemes.
+// Transformers are applied in the order they are added. The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func WithDestNameTransformer(transform func(name string) string) Option {
+	return func(o *options) {
+		o.destNames = append(o.destNames, transform)
+	}
+}
+
+// StripSourcePrefix strips prefix from source field names, so that DbUserName matches UserName.
+// The option has effect only when passed to New, calls fail with ErrInvalidOption otherwise.
+func StripSourcePrefix(prefix string) Option {
+	return WithSourceNameTransformer(func(name string) string { return trimPrefix(name, prefix) })
+}
+
+// StripSourceSuffix strips suffix from source field names. The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func StripSourceSuffix(suffix string) Option {
+	return WithSourceNameTransformer(func(name string) string { return trimSuffix(name, suffix) })
+}
+
+// StripDestPrefix strips prefix from destination field names. The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func StripDestPrefix(prefix string) Option {
+	return WithDestNameTransformer(func(name string) string { return trimPrefix(name, prefix) })
+}
+
+// StripDestSuffix strips suffix from destination field names, so that EmailDTO matches Email.
+// The option has effect only when passed to New, calls fail with ErrInvalidOption otherwise.
+func StripDestSuffix(suffix string) Option {
+	return WithDestNameTransformer(func(name string) string { return trimSuffix(name, suffix) })
+}
+
+// RecognizePrefixes strips the first matching of prefixes from source field names,
+// e.g. RecognizePrefixes("Db", "Get"). The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func RecognizePrefixes(prefixes ...string) Option {
+	return WithSourceNameTransformer(func(name string) string {
+		for _, prefix := range prefixes {
+			if trimmed := trimPrefix(name, prefix); trimmed != name {
+				return trimmed
+			}
+		}
+
+		return name
+	})
+}
+
+// trimPrefix trims prefix of name, unless it's the whole name.
+func trimPrefix(name, prefix string) string {
+	if len(name) == len(prefix) {
+		return name
+	}
+
+	return strings.TrimPrefix(name, prefix)
+}
+
+// trimSuffix trims suffix of name, unless it's the whole name.
+func trimSuffix(name, suffix string) string {
+	if len(name) == len(suffix) {
+		return name
+	}
+
+	return strings.TrimSuffix(name, suffix)
+}
+
+func transformName(name string, transformers []func(string) string) string {
+	for _, transform := range transformers {
+		name = transform(name)
+	}
+
+	return name
+}
+
+// sourceName returns name of source field matching destination field name toName
+// after name transformers are applied, transformed toName if there's none.
+func (m *Mapper) sourceName(fromFields *structFields, toName string) string {
+	name := transformName(toName, m.opts.destNames)
+	if len(m.opts.sourceNames) == 0 {
+		return name
+	}
+
+	for _, meta := range fromFields.list {
+		if transformName(meta.name, m.opts.sourceNames) == name {
+			return meta.name
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,52 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NilSourceError is returned when source is a nil pointer: top-level source of Map,
+// or nested source mapped into non-pointer destination in WithStrictNil mode.
+type NilSourceError struct {
+	// Path is a path of destination field, e.g. "Customer.Address", empty for top-level source.
+	Path string
+}
+
+func (e *NilSourceError) Error() string {
+	if e.Path == "" {
+		return ErrNilSource.Error()
+	}
+
+	return fmt.Sprintf("%s: field %s", ErrNilSource, e.Path)
+}
+
+// Unwrap returns ErrNilSource and ErrNilValue, which is returned for nil sources by earlier versions.
+func (e *NilSourceError) Unwrap() []error {
+	return []error{ErrNilSource, ErrNilValue}
+}
+
+func (e *NilSourceError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *NilSourceError) fieldPath() string {
+	return e.Path
+}
+
+// WithStrictNil makes nil pointer sources mapped into non-pointer destinations fail with *NilSourceError
+// instead of being skipped, e.g. nil *Address field mapped into Address field,
+// since such destinations can't represent missing value.
+func WithStrictNil() Option {
+	return func(o *options) {
+		o.strictNil = true
+	}
+}
+
+// checkNilSource returns *NilSourceError if from is a nil pointer, which can't be mapped into to in strict mode.
+func checkNilSource(st *mapState, from, to reflect.Value) error {
+	if !st.opts.strictNil || from.Kind() != reflect.Ptr || !from.IsNil() || to.Kind() == reflect.Ptr {
+		return nil
+	}
+
+	return &NilSourceError{}
+}
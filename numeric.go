@@ -0,0 +1,159 @@
+package automapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// NumericError is returned in strict numeric mode when value doesn't survive conversion.
+type NumericError struct {
+	// Path is a path of destination field, e.g. "Items[0].Count".
+	Path     string
+	From, To reflect.Type
+	Value    interface{}
+}
+
+func (e *NumericError) Error() string {
+	return fmt.Sprintf("%s: field %s: %v doesn't fit '%s -> %s'", ErrLossyConversion, e.Path, e.Value, e.From, e.To)
+}
+
+func (e *NumericError) Unwrap() error {
+	return ErrLossyConversion
+}
+
+func (e *NumericError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *NumericError) fieldPath() string {
+	return e.Path
+}
+
+// WithNumericConversion enables conversions between integer, unsigned and float fields,
+// which otherwise require converters. Values are converted like Go conversions do,
+// so narrowing conversions may wrap or truncate, see WithStrictNumeric.
+func WithNumericConversion() Option {
+	return func(o *options) {
+		o.numeric = true
+	}
+}
+
+// WithStrictNumeric enables numeric conversions, which fail with *NumericError
+// if value changes, e.g. on int64 to int32 overflow or float to int truncation.
+func WithStrictNumeric() Option {
+	return func(o *options) {
+		o.numeric = true
+		o.strictNumeric = true
+	}
+}
+
+// ConversionError is returned when string field can't be parsed into numeric field
+// in WithStringNumberConversions mode.
+type ConversionError struct {
+	// Path is a path of destination field, e.g. "Items[0].Count".
+	Path     string
+	From, To reflect.Type
+	Value    string
+	// Err is a strconv error.
+	Err error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("%s: field %s: %q can't be converted '%s -> %s': %v", ErrConverter, e.Path, e.Value, e.From, e.To, e.Err)
+}
+
+func (e *ConversionError) Unwrap() []error {
+	return []error{ErrConverter, e.Err}
+}
+
+func (e *ConversionError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *ConversionError) fieldPath() string {
+	return e.Path
+}
+
+// WithStringNumberConversions enables conversions between string and numeric fields with strconv,
+// e.g. "42" into int, and 1.5 into "1.5", which otherwise require converters.
+// Strings which can't be parsed fail with *ConversionError.
+func WithStringNumberConversions() Option {
+	return func(o *options) {
+		o.stringNumbers = true
+	}
+}
+
+func isStringNumber(from, to reflect.Kind) bool {
+	return (from == reflect.String && isNumeric(to)) || (isNumeric(from) && to == reflect.String)
+}
+
+// convertStringNumber parses string from into numeric to, or formats numeric from into string to.
+func convertStringNumber(from, to reflect.Value) error {
+	if from.Kind() == reflect.String {
+		parsed, err := parseBasic(from.String(), to.Type())
+		if err != nil {
+			return &ConversionError{From: from.Type(), To: to.Type(), Value: from.String(), Err: err}
+		}
+
+		to.Set(parsed)
+		return nil
+	}
+
+	switch {
+	case from.CanInt():
+		to.SetString(strconv.FormatInt(from.Int(), 10))
+	case from.CanUint():
+		to.SetString(strconv.FormatUint(from.Uint(), 10))
+	default:
+		to.SetString(strconv.FormatFloat(from.Float(), 'g', -1, from.Type().Bits()))
+	}
+
+	return nil
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func convertNumeric(st *mapState, from, to reflect.Value) error {
+	converted := from.Convert(to.Type())
+	if st.opts.strictNumeric && !isLossless(from, converted) {
+		return &NumericError{From: from.Type(), To: to.Type(), Value: from.Interface()}
+	}
+
+	to.Set(converted)
+	return nil
+}
+
+// isLossless reports whether converted value converts back to from and keeps its sign.
+func isLossless(from, converted reflect.Value) bool {
+	if isFloat(from.Kind()) && math.IsNaN(from.Float()) {
+		return isFloat(converted.Kind())
+	}
+
+	return converted.Convert(from.Type()).Interface() == from.Interface() && isNegative(from) == isNegative(converted)
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isNegative(val reflect.Value) bool {
+	switch {
+	case isFloat(val.Kind()):
+		return val.Float() < 0
+	case val.CanInt():
+		return val.Int() < 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,168 @@
+package automapper
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// additionalPropertiesField is a name of field holding undeclared properties of OpenAPI models.
+const additionalPropertiesField = "AdditionalProperties"
+
+// WithOpenAPI enables conventions of models generated by oapi-codegen and openapi-generator:
+//   - nullable wrappers, types with Get, IsSet and Set methods like NullableString, are unwrapped
+//     into plain fields if set, explicit null clears destination, and plain fields are wrapped;
+//   - entries of source AdditionalProperties map field are mapped into destination fields matching
+//     their keys, and source fields missing in destination are put into its AdditionalProperties map;
+//   - optional pointer fields map into plain fields and back with nil policy, see NilPolicy.
+func WithOpenAPI(policy NilPolicy) Option {
+	return func(o *options) {
+		o.openAPI = true
+		o.nilPolicy = policy
+	}
+}
+
+// nullableMethods are methods of nullable wrapper type.
+type nullableMethods struct {
+	// get and isSet are methods of value, set is method of pointer
+	get, isSet, set reflect.Method
+	ok              bool
+}
+
+// nullablesCache holds nullableMethods by reflect.Type.
+var nullablesCache sync.Map
+
+// nullable returns methods of nullable wrapper type tp, ok is false for other types.
+func nullable(tp reflect.Type) nullableMethods {
+	if cached, ok := nullablesCache.Load(tp); ok {
+		if methods, ok := cached.(nullableMethods); ok {
+			return methods
+		}
+	}
+
+	var methods nullableMethods
+	get, okGet := tp.MethodByName("Get")
+	isSet, okIsSet := tp.MethodByName("IsSet")
+	set, okSet := reflect.PointerTo(tp).MethodByName("Set")
+	if okGet && okIsSet && okSet && tp.Kind() == reflect.Struct &&
+		get.Type.NumIn() == 1 && get.Type.NumOut() == 1 &&
+		isSet.Type.NumIn() == 1 && isSet.Type.NumOut() == 1 && isSet.Type.Out(0).Kind() == reflect.Bool &&
+		set.Type.NumIn() == 2 && set.Type.NumOut() == 0 {
+		methods = nullableMethods{get: get, isSet: isSet, set: set, ok: true}
+	}
+
+	nullablesCache.Store(tp, methods)
+	return methods
+}
+
+// mapNullable unwraps nullable from or wraps from into nullable to, reporting whether
+// either of them is nullable.
+func (m *Mapper) mapNullable(st *mapState, from, to reflect.Value) (bool, error) {
+	if from.Type() == to.Type() {
+		return false, nil
+	}
+
+	if methods := nullable(from.Type()); methods.ok {
+		if !from.Method(methods.isSet.Index).Call(nil)[0].Bool() {
+			return true, nil
+		}
+
+		value := from.Method(methods.get.Index).Call(nil)[0]
+		if value.IsZero() {
+			// explicit null
+			to.Set(reflect.Zero(to.Type()))
+			return true, nil
+		}
+
+		return true, m.mapValue(st, value, to)
+	}
+
+	if methods := nullable(to.Type()); methods.ok {
+		value := reflect.New(methods.set.Type.In(1)).Elem()
+		err := m.mapValue(st, from, value)
+		if err != nil {
+			return true, err
+		}
+
+		to.Addr().Method(methods.set.Index).Call([]reflect.Value{value})
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// mapAdditionalProperties maps entries of from AdditionalProperties into fields of to,
+// and fields of from missing in to into to AdditionalProperties.
+func (m *Mapper) mapAdditionalProperties(st *mapState, from, to reflect.Value) error {
+	if props, ok := additionalProperties(from); ok {
+		iter := props.MapRange()
+		for iter.Next() {
+			toVal, ok := destFieldByKey(st, to, iter.Key().String())
+			if !ok || iter.Key().String() == additionalPropertiesField {
+				continue
+			}
+
+			value := iter.Value()
+			if value.Kind() == reflect.Interface {
+				value = value.Elem()
+			}
+
+			if !value.IsValid() || value.IsZero() {
+				continue
+			}
+
+			err := m.mapValue(st, value, toVal)
+			if err != nil {
+				return withFieldPath(err, iter.Key().String())
+			}
+		}
+	}
+
+	props, ok := additionalProperties(to)
+	if !ok {
+		return nil
+	}
+
+	elemType := props.Type().Elem()
+	toFields := cachedFields(to.Type())
+	for _, meta := range cachedFields(from.Type()).list {
+		value := from.Field(meta.index)
+		if _, exists := toFields.fieldByKey(meta.name); exists || !meta.exported || value.IsZero() ||
+			!value.Type().AssignableTo(elemType) {
+			continue
+		}
+
+		if props.IsNil() {
+			props.Set(reflect.MakeMap(props.Type()))
+		}
+
+		props.SetMapIndex(reflect.ValueOf(jsonName(from.Type().Field(meta.index))), value)
+	}
+
+	return nil
+}
+
+// additionalProperties returns AdditionalProperties map field of struct val.
+func additionalProperties(val reflect.Value) (reflect.Value, bool) {
+	meta, ok := cachedFields(val.Type()).field(additionalPropertiesField)
+	if !ok || !meta.exported {
+		return reflect.Value{}, false
+	}
+
+	props := val.Field(meta.index)
+	if props.Kind() != reflect.Map || props.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+
+	return props, true
+}
+
+// jsonName returns name of field in JSON.
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
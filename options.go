@@ -0,0 +1,91 @@
+package automapper
+
+import "time"
+
+// Option configures mapping.
+type Option func(o *options)
+
+type options struct {
+	only, except []string
+	protobuf     bool
+	// parseStrings enables built-in parsing of strings into basic kinds.
+	parseStrings bool
+	noPooling    bool
+	// numeric enables conversions between numeric kinds, strictNumeric makes lossy ones fail.
+	numeric, strictNumeric bool
+	// timeLocation is a location mapped time.Time values are converted to.
+	timeLocation *time.Location
+	// stripMonotonic makes mapped time.Time values drop monotonic clock reading.
+	stripMonotonic bool
+	maxDepth       int
+	planCacheSize  int
+	// stringNumbers enables conversions between strings and numeric kinds.
+	stringNumbers bool
+	// graphql enables graphql tag names, nilPolicy sets mapping of zero optional values.
+	graphql   bool
+	nilPolicy NilPolicy
+	// openAPI enables nullable wrappers and additional properties.
+	openAPI bool
+	// rawJSON enables mapping between json.RawMessage and structs or maps via encoding/json.
+	rawJSON bool
+	// copyInterfaces makes values held by interface fields of identical types copied.
+	copyInterfaces bool
+	// sourceNames and destNames transform field names before matching.
+	sourceNames, destNames []func(string) string
+	// strictNil makes nil pointers mapped into non-pointer destinations fail.
+	strictNil bool
+	// boolStrings enables conversions between bools and strings, see WithBoolStrings.
+	boolStrings *BoolStrings
+	// weak enables coercions of loosely-typed inputs.
+	weak bool
+	// specialFloats sets mapping of NaN and ±Inf, which are replaced with floatSubstitute by substitute policy.
+	specialFloats   specialFloatPolicy
+	floatSubstitute float64
+	// skipEqual leaves fields untouched when mapped values are equal to them.
+	skipEqual bool
+	// converters are converters of the call, converterErr is set if any of them is invalid.
+	converters   converterTable
+	converterErr error
+	// optionErr is set if options, which have effect only when passed to New, are passed to the call.
+	optionErr error
+	// unsafeCopy enables memory copies of structs with identical layouts.
+	unsafeCopy bool
+	// strictSource fails mapping of structs with unused source fields.
+	strictSource bool
+}
+
+// copyTo copies options to dst, so that dst is safe to modify by per-call options.
+func (o *options) copyTo(dst *options) {
+	*dst = *o
+	// limit capacity so appends don't write to the shared arrays
+	dst.only = dst.only[:len(dst.only):len(dst.only)]
+	dst.except = dst.except[:len(dst.except):len(dst.except)]
+	dst.sourceNames = dst.sourceNames[:len(dst.sourceNames):len(dst.sourceNames)]
+	dst.destNames = dst.destNames[:len(dst.destNames):len(dst.destNames)]
+}
+
+// err returns error of invalid options of the call.
+func (o *options) err() error {
+	if o.converterErr != nil {
+		return o.converterErr
+	}
+
+	return o.optionErr
+}
+
+// Only restricts mapping to listed field paths. Nested fields are separated by dots,
+// e.g. "Address.City", and paths of slice fields apply to every element.
+// Path segments match field names or mapper tags ignoring case and underscores,
+// so "display_name" matches field DisplayName, which makes google.protobuf.FieldMask paths usable.
+func Only(paths ...string) Option {
+	return func(o *options) {
+		o.only = append(o.only, paths...)
+	}
+}
+
+// Except excludes listed field paths from mapping. Paths have the same format as in Only.
+func Except(paths ...string) Option {
+	return func(o *options) {
+		o.except = append(o.except, paths...)
+	}
+}
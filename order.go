@@ -0,0 +1,68 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// sortedKeys returns keys of map m in deterministic order, so that mapping of its entries
+// and errors reported for them don't depend on map iteration order.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+// lessKey orders map keys by their values, keys of other kinds are ordered by their formatted values.
+func lessKey(a, b reflect.Value) bool {
+	switch {
+	case a.Kind() == reflect.String:
+		return a.String() < b.String()
+	case a.CanInt():
+		return a.Int() < b.Int()
+	case a.CanUint():
+		return a.Uint() < b.Uint()
+	case a.CanFloat():
+		return a.Float() < b.Float()
+	case a.Kind() == reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	}
+}
+
+// sortByFields sorts external keys, e.g. string map keys, in declaration order of fields of struct type tp
+// they match, so that errors are reported for the first declared field. Keys not matching fields are the last.
+func sortByFields(tp reflect.Type, keys []string) {
+	fields := cachedFields(tp)
+	index := func(key string) int {
+		if meta, ok := fields.fieldByKey(key); ok {
+			return meta.index
+		}
+
+		return tp.NumField()
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if a, b := index(keys[i]), index(keys[j]); a != b {
+			return a < b
+		}
+
+		return keys[i] < keys[j]
+	})
+}
+
+// keysByFields returns keys of m sorted by sortByFields.
+func keysByFields[V any](m map[string]V, tp reflect.Type) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sortByFields(tp, keys)
+	return keys
+}
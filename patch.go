@@ -0,0 +1,274 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Patch applies patch to the struct pointed by to with JSON merge patch (RFC 7396) semantics.
+// patch must be a pointer to a struct or a map[string]interface{} (or a pointer to it).
+//
+// For struct patches, nil pointer fields are left untouched and non-nil ones are dereferenced
+// and applied to the destination. For map patches, e.g. decoded with encoding/json, every present
+// key is applied to the destination field with the same name, a key with nil value (explicit JSON null)
+// clears the destination field, and nested maps are merged into nested structs.
+// Map keys match destination fields the same way MapMasked paths do. JSON numbers, float64 or json.Number,
+// are converted into numeric fields if they fit them, []interface{} values are patched into slices
+// and arrays element by element, and map[string]interface{} values into maps entry by entry.
+// Options apply to this call only, errors are *Error like errors of Map.
+func (m *Mapper) Patch(patch, to interface{}, opts ...Option) error {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	return m.translateError(m.patch(st, patch, to))
+}
+
+func (m *Mapper) patch(st *mapState, patch, to interface{}) error {
+	if err := st.opts.err(); err != nil {
+		return err
+	}
+
+	valTo := reflect.ValueOf(to)
+	if valTo.Kind() != reflect.Ptr || !isStructOrPtrToStruct(valTo.Type()) {
+		return ErrNotAPtr
+	}
+
+	valPatch := reflect.ValueOf(patch)
+	if valPatch.Kind() == reflect.Ptr {
+		valPatch = valPatch.Elem()
+	}
+
+	switch valPatch.Kind() {
+	case reflect.Map:
+		patchMap, ok := valPatch.Interface().(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w '%s'", ErrUnsupportedPatch, valPatch.Type())
+		}
+
+		return m.patchStructFromMap(st, patchMap, valTo.Elem())
+	case reflect.Struct:
+		return m.patchStructFromStruct(st, valPatch, valTo.Elem())
+	default:
+		return fmt.Errorf("%w '%s'", ErrUnsupportedPatch, valPatch.Type())
+	}
+}
+
+func (m *Mapper) patchStructFromMap(st *mapState, patch map[string]interface{}, to reflect.Value) error {
+	for _, key := range keysByFields(patch, to.Type()) {
+		value := patch[key]
+		toVal, ok := destFieldByKey(st, to, key)
+		if !ok {
+			continue
+		}
+
+		// explicit null clears destination, unless it's tagged with skipzero
+		meta, _ := cachedFields(to.Type()).fieldByKey(key)
+		if value == nil {
+			if !meta.hasOption(tagSkipZero) {
+				toVal.Set(reflect.Zero(toVal.Type()))
+			}
+
+			continue
+		}
+
+		if err := m.patchValue(st, reflect.ValueOf(value), toVal); err != nil {
+			return withFieldPath(err, meta.name)
+		}
+	}
+
+	return nil
+}
+
+// patchNestedStruct merges patch into struct or pointer to struct,
+// allocating the destination if it's a nil pointer.
+func (m *Mapper) patchNestedStruct(st *mapState, patch map[string]interface{}, to reflect.Value) error {
+	if to.Kind() != reflect.Ptr {
+		return m.patchStructFromMap(st, patch, to)
+	}
+
+	if to.IsNil() {
+		to.Set(reflect.New(to.Type().Elem()))
+	}
+
+	return m.patchStructFromMap(st, patch, to.Elem())
+}
+
+func (m *Mapper) patchStructFromStruct(st *mapState, patch, to reflect.Value) error {
+	fromFields, toFields := cachedFields(patch.Type()), cachedFields(to.Type())
+	for i := range toFields.list {
+		toMeta := &toFields.list[i]
+		toVal := to.Field(toMeta.index)
+		if !toVal.CanSet() {
+			continue
+		}
+
+		// nil fields are skipped by sourceField
+		fromVal, ok := sourceField(st, patch, fromFields, toMeta.name)
+		if !ok || (toMeta.hasOption(tagSkipZero) && fromVal.IsZero()) {
+			continue
+		}
+
+		err := m.patchValue(st, fromVal, toVal)
+		if err != nil {
+			return withFieldPath(err, toMeta.name)
+		}
+	}
+
+	return nil
+}
+
+// patchValue assigns from to to using mapper strategies,
+// dereferencing optional source values and allocating optional destinations.
+func (m *Mapper) patchValue(st *mapState, from, to reflect.Value) error {
+	if from.Kind() == reflect.Interface {
+		if from.IsNil() {
+			to.Set(reflect.Zero(to.Type()))
+			return nil
+		}
+
+		from = from.Elem()
+	}
+
+	if patched, err := m.patchJSON(st, from, to); patched {
+		return err
+	}
+
+	mappingType := m.detectMappingType(from.Type(), to.Type())
+	if mappingType != unsupported {
+		return m.strats[mappingType](st, from, to)
+	}
+
+	if from.Type().AssignableTo(to.Type()) {
+		to.Set(from)
+		return nil
+	}
+
+	if from.Kind() == reflect.Ptr && !from.IsNil() {
+		return m.patchValue(st, from.Elem(), to)
+	}
+
+	if to.Kind() == reflect.Ptr {
+		elem := reflect.New(to.Type().Elem())
+		err := m.patchValue(st, from, elem.Elem())
+		if err != nil {
+			return err
+		}
+
+		to.Set(elem)
+		return nil
+	}
+
+	if isJSONNumber(from.Type()) && isNumeric(to.Kind()) {
+		return patchNumber(from, to)
+	}
+
+	return m.missingConverter(from, to)
+}
+
+// patchJSON patches values decoded by encoding/json, which types differ from destination ones:
+// nested objects are merged into structs, arrays are patched element by element and objects
+// into maps entry by entry. It reports whether from is such a value.
+func (m *Mapper) patchJSON(st *mapState, from, to reflect.Value) (bool, error) {
+	if (from.Kind() != reflect.Map && from.Kind() != reflect.Slice) ||
+		from.Type().Elem().Kind() != reflect.Interface || from.Type() == to.Type() {
+		return false, nil
+	}
+
+	switch {
+	case from.Kind() == reflect.Map && from.Type().Key().Kind() == reflect.String && isStructOrPtrToStruct(to.Type()):
+		patch, ok := from.Interface().(map[string]interface{})
+		return ok, m.patchNestedStruct(st, patch, to)
+	case from.Kind() == reflect.Slice && (to.Kind() == reflect.Slice || to.Kind() == reflect.Array):
+		return true, m.patchElems(st, from, to)
+	case from.Kind() == reflect.Map && to.Kind() == reflect.Map && to.Type().Key().Kind() == reflect.String:
+		return true, m.patchEntries(st, from, to)
+	default:
+		return false, nil
+	}
+}
+
+// patchElems replaces elements of slice or array to with patched elements of from.
+func (m *Mapper) patchElems(st *mapState, from, to reflect.Value) error {
+	var elems reflect.Value
+	if to.Kind() == reflect.Slice {
+		elems = reflect.MakeSlice(to.Type(), from.Len(), from.Len())
+	} else {
+		elems = reflect.New(to.Type()).Elem()
+	}
+
+	for i := 0; i < from.Len() && i < elems.Len(); i++ {
+		if err := m.patchValue(st, from.Index(i), elems.Index(i)); err != nil {
+			return withFieldPath(err, "["+strconv.Itoa(i)+"]")
+		}
+	}
+
+	to.Set(elems)
+	return nil
+}
+
+// patchEntries replaces map to with entries of from patched into values of to.
+func (m *Mapper) patchEntries(st *mapState, from, to reflect.Value) error {
+	entries := reflect.MakeMapWithSize(to.Type(), from.Len())
+	for _, key := range sortedKeys(from) {
+		elem := reflect.New(to.Type().Elem()).Elem()
+		if err := m.patchValue(st, from.MapIndex(key), elem); err != nil {
+			return withFieldPath(err, "["+key.String()+"]")
+		}
+
+		entries.SetMapIndex(key.Convert(to.Type().Key()), elem)
+	}
+
+	to.Set(entries)
+	return nil
+}
+
+// isJSONNumber reports whether tp is a type of numbers decoded by encoding/json.
+func isJSONNumber(tp reflect.Type) bool {
+	return tp == jsonNumberType || tp.Kind() == reflect.Float64
+}
+
+// patchNumber sets JSON number from into numeric to, failing with *NumericError
+// if it doesn't fit integer to, e.g. 1.5 into int.
+func patchNumber(from, to reflect.Value) error {
+	if from.Type() == jsonNumberType {
+		parsed, err := parseBasic(from.String(), to.Type())
+		if err != nil {
+			return &ConversionError{From: from.Type(), To: to.Type(), Value: from.String(), Err: err}
+		}
+
+		to.Set(parsed)
+		return nil
+	}
+
+	converted := from.Convert(to.Type())
+	if !isFloat(to.Kind()) && !isLossless(from, converted) {
+		return &NumericError{From: from.Type(), To: to.Type(), Value: from.Interface()}
+	}
+
+	to.Set(converted)
+	return nil
+}
+
+// sourceField returns non-zero value of source field with name,
+// or the result of its getter in protobuf mode.
+func sourceField(st *mapState, from reflect.Value, fromFields *structFields, name string) (reflect.Value, bool) {
+	if st.opts.protobuf {
+		if getter, ok := protobufGetter(from, name); ok {
+			val := getter.Call(nil)[0]
+			return val, !val.IsZero()
+		}
+	}
+
+	meta, ok := fromFields.field(name)
+	if !ok || !meta.exported || (st.opts.protobuf && isProtobufInternalField(name)) {
+		return reflect.Value{}, false
+	}
+
+	// skip zero or nil values
+	val := from.Field(meta.index)
+	if val.IsZero() {
+		return reflect.Value{}, false
+	}
+
+	return val, true
+}
@@ -0,0 +1,510 @@
+package automapper
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+)
+
+// fieldOp is a precomputed mapping of a single destination field.
+type fieldOp struct {
+	name               string
+	fromIndex, toIndex int
+	// fromPath and toPath are index paths of fields of flattened embedded structs, see Flatten.
+	fromPath, toPath []int
+	fromType, toType reflect.Type
+	// getter is index of source getter method in protobuf mode, -1 if source field is read directly.
+	getter     int
+	strategy   supportedType
+	mapperFunc mapperFunc
+	// converter is resolved converter of converterFunc strategy,
+	// named is set if it's selected by tag, so that per-call converters don't replace it.
+	converter reflect.Value
+	named     bool
+	// err is returned instead of mapping when source value is not zero,
+	// e.g. when converter is missing for field types.
+	err error
+	// tagErr is an error of invalid tag of source or destination field,
+	// which is returned whenever the field is mapped, see parseTagOptions.
+	tagErr error
+	// skipZero keeps non-zero destination field if mapped value is zero, see tagSkipZero.
+	skipZero bool
+	// require fails mapping of zero or missing source, which fromType is nil, see tagRequire.
+	require bool
+	// money is a pair of source fields joined into destination money, see RegisterMoney.
+	money *moneyPair
+	// transform transforms mapped string, see stringTransforms.
+	transform func(string) string
+	// oneofs are wrappers used to populate interface destination without source field.
+	oneofs []oneofCase
+	// remain are source fields collected into destination map without source field, see remainOption.
+	remain []remainField
+	// remainKey is a key of entry of source remain map at fromIndex, which is mapped into destination field.
+	remainKey string
+}
+
+// oneofCase is a wrapper type populated by op, which toIndex is always 0.
+type oneofCase struct {
+	wrapper reflect.Type
+	op      fieldOp
+}
+
+// WithPlanCacheSize limits number of cached plans of struct type pairs, least recently used plans
+// are evicted and rebuilt on next use. It bounds memory of services mapping many dynamically
+// created types. Zero size means no limit. The option has effect only when passed to New,
+// calls fail with ErrInvalidOption otherwise.
+func WithPlanCacheSize(size int) Option {
+	return func(o *options) {
+		o.planCacheSize = size
+	}
+}
+
+// structPlan returns plan of mapping from struct type into to struct type,
+// building and caching it on first use.
+func (m *Mapper) structPlan(st *mapState, from, to reflect.Type) []fieldOp {
+	mappingInfo := structMappingInfo{from: from, to: to, protobuf: st.opts.protobuf, graphql: st.opts.graphql}
+	if m.frozen {
+		if plan, ok := m.knownMappings[mappingInfo]; ok {
+			return plan
+		}
+
+		return m.buildPlan(mappingInfo)
+	}
+
+	plan, ok := m.cachedPlan(mappingInfo)
+	if ok {
+		m.stats.planHits.Add(1)
+		return plan
+	}
+
+	m.stats.planMisses.Add(1)
+	m.mu.RLock()
+	generation := m.planGeneration
+	m.mu.RUnlock()
+	// plans of nested structs are built lazily on their own first use,
+	// so building doesn't recurse and concurrent builds produce the same plan
+	plan = m.buildPlan(mappingInfo)
+	m.mu.Lock()
+	// plans built before configuration changes, e.g. concurrent Set, are not cached
+	if generation == m.planGeneration {
+		m.knownMappings[mappingInfo] = plan
+		m.usePlan(mappingInfo)
+	}
+	m.mu.Unlock()
+	return plan
+}
+
+// cachedPlan returns cached plan, marking it as recently used in bounded cache.
+func (m *Mapper) cachedPlan(mappingInfo structMappingInfo) ([]fieldOp, bool) {
+	if m.opts.planCacheSize <= 0 {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		plan, ok := m.knownMappings[mappingInfo]
+		return plan, ok
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	plan, ok := m.knownMappings[mappingInfo]
+	if ok {
+		m.usePlan(mappingInfo)
+	}
+
+	return plan, ok
+}
+
+// usePlan moves plan to front of LRU list of bounded cache, evicting plans over its size.
+// m.mu must be locked.
+func (m *Mapper) usePlan(mappingInfo structMappingInfo) {
+	if m.opts.planCacheSize <= 0 {
+		return
+	}
+
+	if elem, ok := m.planElems[mappingInfo]; ok {
+		m.planLRU.MoveToFront(elem)
+		return
+	}
+
+	m.planElems[mappingInfo] = m.planLRU.PushFront(mappingInfo)
+	for m.planLRU.Len() > m.opts.planCacheSize {
+		oldest, _ := m.planLRU.Remove(m.planLRU.Back()).(structMappingInfo)
+		delete(m.planElems, oldest)
+		delete(m.knownMappings, oldest)
+		m.stats.planEvictions.Add(1)
+	}
+}
+
+// resetPlans drops built plans, they must be rebuilt after configuration changes.
+func (m *Mapper) resetPlans() {
+	m.mu.Lock()
+	m.planGeneration++
+	m.knownMappings = make(map[structMappingInfo][]fieldOp)
+	m.planLRU = list.New()
+	m.planElems = make(map[structMappingInfo]*list.Element)
+	m.mu.Unlock()
+}
+
+func (m *Mapper) buildPlan(mappingInfo structMappingInfo) []fieldOp {
+	if plan, ok := m.restorePlan(mappingInfo); ok {
+		return plan
+	}
+
+	fromFields, toFields := mappingInfo.fields(mappingInfo.from), mappingInfo.fields(mappingInfo.to)
+	plan := make([]fieldOp, 0, len(toFields.list))
+	fromRemain, hasFromRemain := sourceRemain(mappingInfo, fromFields)
+	var remain *fieldMeta
+	for i := range toFields.list {
+		toMeta := &toFields.list[i]
+		if !toMeta.exported || (mappingInfo.protobuf && isProtobufInternalField(toMeta.name)) {
+			continue
+		}
+
+		if toMeta.err != nil {
+			plan = append(plan, invalidTagOp(toMeta, mappingInfo.to.Field(toMeta.index).Type))
+			continue
+		}
+
+		if toMeta.hasOption(tagIgnore) {
+			continue
+		}
+
+		if isRemainField(toMeta, mappingInfo.to.Field(toMeta.index).Type) {
+			remain = toMeta
+			continue
+		}
+
+		fromName := m.sourceName(fromFields, toMeta.name)
+		if embedded, ok := m.flattenedField(mappingInfo.to, toMeta.index); ok {
+			if _, ok = fromFields.field(fromName); !ok {
+				plan = append(plan, m.flattenedDestOps(mappingInfo, fromFields, toMeta, embedded)...)
+				continue
+			}
+		}
+
+		toType := mappingInfo.to.Field(toMeta.index).Type
+		op, ok := m.newFieldOp(mappingInfo, fromFields, fromName, toType, toMeta.opts)
+		if !ok {
+			op, ok = m.flattenedSourceOp(mappingInfo, fromFields, fromName, toType, toMeta.opts)
+		}
+
+		if !ok && mappingInfo.protobuf && toType.Kind() == reflect.Interface {
+			op = fieldOp{name: toMeta.name, oneofs: m.oneofCases(mappingInfo, fromFields, toType)}
+			ok = len(op.oneofs) > 0
+		}
+
+		if !ok && hasFromRemain {
+			op, ok = m.remainKeyOp(mappingInfo, fromRemain, fromName, toType), true
+		}
+
+		if !ok {
+			op, ok = m.moneyFieldOp(mappingInfo, fromFields, fromName, toType)
+		}
+
+		// required fields without source fail mapping
+		if !ok && toMeta.hasOption(tagRequire) {
+			op, ok = fieldOp{toType: toType, getter: -1}, true
+		}
+
+		if ok {
+			op.name, op.toIndex = toMeta.name, toMeta.index
+			op.setDestTags(toMeta)
+			plan = append(plan, op)
+		}
+	}
+
+	if remain != nil {
+		if fields := remainFields(mappingInfo, fromFields, plan); len(fields) > 0 {
+			toType := mappingInfo.to.Field(remain.index).Type
+			plan = append(plan, fieldOp{name: remain.name, toIndex: remain.index, toType: toType, getter: -1, remain: fields})
+		}
+	}
+
+	return plan
+}
+
+// newFieldOp resolves source of field with name and strategy of mapping it into toType.
+// Tag options of source field and toOpts of destination field may select strategy, e.g. unix.
+func (m *Mapper) newFieldOp(
+	mappingInfo structMappingInfo, fromFields *structFields, name string, toType reflect.Type, toOpts tagOptions,
+) (fieldOp, bool) {
+	op := fieldOp{name: name, getter: -1}
+	opts := toOpts
+	if meta, ok := fromFields.field(name); ok {
+		opts = append(meta.opts[:len(meta.opts):len(meta.opts)], toOpts...)
+	}
+
+	var fromType reflect.Type
+	if getter, ok := protobufGetterMethod(mappingInfo, name); ok {
+		op.getter = getter.Index
+		fromType = getter.Type.Out(0)
+	} else {
+		meta, ok := fromFields.field(name)
+		if !ok || !meta.exported || meta.hasOption(tagIgnore) || (mappingInfo.protobuf && isProtobufInternalField(name)) {
+			return fieldOp{}, false
+		}
+
+		op.fromIndex = meta.index
+		fromType = mappingInfo.from.Field(meta.index).Type
+	}
+
+	op.fromType, op.toType = fromType, toType
+	if meta, ok := fromFields.field(name); ok && meta.err != nil {
+		op.tagErr = meta.err
+		return op, true
+	}
+
+	if name, ok := opts.value(tagConverter); ok {
+		m.bindNamed(&op, name)
+		return op, true
+	}
+
+	if m.bindMoney(&op) {
+		return op, true
+	}
+
+	if layout, ok := opts.value(tagTimeFormat); ok && bindTimeFormat(&op, layout) {
+		return op, true
+	}
+
+	if strategy, ok := epochStrategy(opts, fromType, toType); ok {
+		m.bindStrategy(&op, strategy)
+		return op, true
+	}
+
+	mappingType := m.detectMappingType(fromType, toType)
+	if mappingType == unsupported {
+		op.err = fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromType, toType)
+		return op, true
+	}
+
+	m.bindStrategy(&op, mappingType)
+	return op, true
+}
+
+// setDestTags sets options of op selected by tag options of its destination field.
+func (op *fieldOp) setDestTags(toMeta *fieldMeta) {
+	op.skipZero, op.require = toMeta.hasOption(tagSkipZero), toMeta.hasOption(tagRequire)
+	transform, err := toMeta.opts.transform(toMeta.name, op.toType)
+	if err != nil {
+		op.tagErr = err
+	}
+
+	op.transform = transform
+}
+
+// invalidTagOp returns op failing mapping of destination field with invalid tag.
+func invalidTagOp(toMeta *fieldMeta, toType reflect.Type) fieldOp {
+	return fieldOp{name: toMeta.name, toIndex: toMeta.index, toType: toType, getter: -1, tagErr: toMeta.err}
+}
+
+// bindStrategy sets strategy of op, binding converter of converterFunc strategy,
+// so that execution doesn't look it up.
+func (m *Mapper) bindStrategy(op *fieldOp, strategy supportedType) {
+	op.strategy = strategy
+	op.mapperFunc = m.strats[strategy]
+	if strategy != converterFunc {
+		return
+	}
+
+	var shape converterShape
+	op.converter, shape, _ = m.converterTable().lookup(op.fromType, op.toType)
+	converter := op.converter
+	op.mapperFunc = func(st *mapState, fromVal, toVal reflect.Value) error {
+		return m.callShaped(converter, shape, fromVal, toVal)
+	}
+}
+
+// heldStruct returns struct pointed by pointer held in interface value to,
+// e.g. *UserDTO held in field of type any.
+func heldStruct(to reflect.Value) (reflect.Value, bool) {
+	if to.Kind() != reflect.Interface || to.IsNil() {
+		return reflect.Value{}, false
+	}
+
+	held := to.Elem()
+	if held.Kind() != reflect.Ptr || held.IsNil() || held.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	return held.Elem(), true
+}
+
+// source returns source value of op.
+func (op *fieldOp) source(from reflect.Value) reflect.Value {
+	if op.remainKey != "" {
+		return remainValue(from.Field(op.fromIndex), op.remainKey, op.fromType)
+	}
+
+	if len(op.fromPath) > 0 {
+		embedded := from.Field(op.fromPath[0])
+		if embedded.Kind() == reflect.Ptr {
+			if embedded.IsNil() {
+				return reflect.Zero(op.fromType)
+			}
+
+			embedded = embedded.Elem()
+		}
+
+		return embedded.Field(op.fromPath[1])
+	}
+
+	if op.getter < 0 {
+		return from.Field(op.fromIndex)
+	}
+
+	if !from.CanAddr() {
+		// getters have pointer receivers
+		ptr := reflect.New(from.Type())
+		ptr.Elem().Set(from)
+		from = ptr.Elem()
+	}
+
+	return from.Addr().Method(op.getter).Call(nil)[0]
+}
+
+// target returns destination field of op in struct to.
+func (op *fieldOp) target(to reflect.Value) reflect.Value {
+	if len(op.toPath) > 0 {
+		return fieldByPath(to, op.toPath)
+	}
+
+	return to.Field(op.toIndex)
+}
+
+func (m *Mapper) execFieldOp(st *mapState, op *fieldOp, from, to reflect.Value) error {
+	if op.tagErr != nil {
+		return op.tagErr
+	}
+
+	if op.require && op.fromType == nil {
+		return &MissingSourceError{}
+	}
+
+	if op.skipZero && !to.IsZero() {
+		return m.mapNonZero(st, op, from, to)
+	}
+
+	// structs mapped field by field track changes of their fields, other values are compared as a whole
+	if (st.opts.skipEqual || st.changes != nil) && !mapsFields(st, op, to) {
+		return m.mapChanged(st, op, from, to)
+	}
+
+	err := m.mapField(st, op, from, to)
+	if err == nil && st.opts.specialFloats != specialFloatsPass {
+		err = checkSpecialFloat(st, to)
+	}
+
+	return err
+}
+
+// mapNonZero maps field of op into copy of non-zero to, which is set into to unless it's zero.
+// Changes tracked while mapping zero value are dropped.
+func (m *Mapper) mapNonZero(st *mapState, op *fieldOp, from, to reflect.Value) error {
+	tracked := 0
+	if st.changes != nil {
+		tracked = len(*st.changes)
+	}
+
+	plain := *op
+	plain.skipZero = false
+	mapped := reflect.New(to.Type()).Elem()
+	mapped.Set(to)
+	err := m.execFieldOp(st, &plain, from, mapped)
+	if err != nil {
+		return err
+	}
+
+	if mapped.IsZero() {
+		if st.changes != nil {
+			*st.changes = (*st.changes)[:tracked]
+		}
+
+		return nil
+	}
+
+	to.Set(mapped)
+	return nil
+}
+
+// mapField maps field of op, see execFieldOp, transforming mapped string.
+func (m *Mapper) mapField(st *mapState, op *fieldOp, from, to reflect.Value) error {
+	err := m.mapSource(st, op, from, to)
+	if err == nil && op.transform != nil {
+		to.SetString(op.transform(to.String()))
+	}
+
+	return err
+}
+
+// mapSource maps source field of op into to.
+func (m *Mapper) mapSource(st *mapState, op *fieldOp, from, to reflect.Value) error {
+	if len(op.oneofs) > 0 {
+		return mapOneof(st, op.oneofs, from, to)
+	}
+
+	if len(op.remain) > 0 {
+		return m.mapRemain(st, op.remain, from, to)
+	}
+
+	if op.money != nil {
+		return mapMoneyPair(op, from, to)
+	}
+
+	fromVal := op.source(from)
+	if err := checkNilSource(st, fromVal, to); err != nil {
+		return err
+	}
+
+	// skip zero or nil values
+	if fromVal.IsZero() {
+		if op.require {
+			return &MissingSourceError{}
+		}
+
+		if st.opts.nilPolicy == NilZero && op.err == nil {
+			setZeroOptional(fromVal, to)
+		}
+
+		return nil
+	}
+
+	if st.opts.openAPI {
+		if ok, err := m.mapNullable(st, fromVal, to); ok {
+			return err
+		}
+	}
+
+	if converter, shape, ok := scopedConverter(st, op.fromType, op.toType); ok && !op.named {
+		return m.callShaped(converter, shape, fromVal, to)
+	}
+
+	if op.err != nil {
+		if lifted := m.liftedScoped(st, op.fromType, op.toType); lifted != nil {
+			return lifted(st, fromVal, to)
+		}
+
+		if held, ok := heldStruct(to); ok {
+			return m.mapValue(st, fromVal, held)
+		}
+
+		if st.opts.rawJSON && isRawJSON(fromVal.Type(), to.Type()) {
+			return mapRawJSON(fromVal, to)
+		}
+
+		if converted, err := m.mapConverted(st, fromVal, to); converted {
+			return err
+		}
+
+		if st.opts.weak {
+			return m.mapWeak(st, fromVal, to)
+		}
+
+		if m.fallback != nil {
+			return m.missingConverter(fromVal, to)
+		}
+
+		return op.err
+	}
+
+	return op.mapperFunc(st, fromVal, to)
+}
@@ -0,0 +1,447 @@
+package automapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// exportedPlans is a serializable set of plans with the configuration they were built with.
+type exportedPlans struct {
+	Converters  []string       `json:"converters"`
+	Impls       []string       `json:"impls"`
+	Flattened   []string       `json:"flattened,omitempty"`
+	Strategies  []string       `json:"strategies,omitempty"`
+	Variants    []string       `json:"variants,omitempty"`
+	Moneys      []string       `json:"moneys,omitempty"`
+	CloneMethod string         `json:"cloneMethod,omitempty"`
+	Plans       []exportedPlan `json:"plans"`
+}
+
+// exportedPlan is a serializable plan of mapping between two struct types.
+type exportedPlan struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Protobuf bool   `json:"protobuf,omitempty"`
+	GraphQL  bool   `json:"graphql,omitempty"`
+	// signatures detect changes of struct types since export
+	FromSignature string `json:"fromSignature"`
+	ToSignature   string `json:"toSignature"`
+	// Sources are names of source fields matched by destination fields, which depend on name transformers
+	Sources []string        `json:"sources,omitempty"`
+	Fields  []exportedField `json:"fields"`
+}
+
+type exportedField struct {
+	Name      string           `json:"name"`
+	FromIndex int              `json:"fromIndex"`
+	ToIndex   int              `json:"toIndex"`
+	Getter    int              `json:"getter"`
+	FromPath  []int            `json:"fromPath,omitempty"`
+	ToPath    []int            `json:"toPath,omitempty"`
+	Strategy  string           `json:"strategy"`
+	Converter string           `json:"converter,omitempty"`
+	Wrapper   string           `json:"wrapper,omitempty"`
+	Oneofs    []exportedField  `json:"oneofs,omitempty"`
+	Remain    []exportedRemain `json:"remain,omitempty"`
+	RemainKey string           `json:"remainKey,omitempty"`
+	// NoSource marks required fields without source, see tagRequire.
+	NoSource bool `json:"noSource,omitempty"`
+}
+
+type exportedRemain struct {
+	Index    int      `json:"index"`
+	Key      string   `json:"key,omitempty"`
+	Consumed []string `json:"consumed,omitempty"`
+}
+
+type importedPlanKey struct {
+	from, to          string
+	protobuf, graphql bool
+}
+
+// ExportPlans writes plans built so far as JSON, so that they can be loaded with ImportPlans
+// at startup instead of being built on first use. Plans of unnamed struct types aren't exported.
+func (m *Mapper) ExportPlans(w io.Writer) error {
+	m.mu.RLock()
+	exported := m.planConfig()
+	for mappingInfo, plan := range m.knownMappings {
+		from, to := typeName(mappingInfo.from), typeName(mappingInfo.to)
+		if from == "" || to == "" {
+			continue
+		}
+
+		exported.Plans = append(exported.Plans, exportedPlan{
+			From:          from,
+			To:            to,
+			Protobuf:      mappingInfo.protobuf,
+			GraphQL:       mappingInfo.graphql,
+			FromSignature: typeSignature(mappingInfo.from),
+			ToSignature:   typeSignature(mappingInfo.to),
+			Sources:       m.sourceNames(mappingInfo),
+			Fields:        m.exportFields(plan),
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(exported.Plans, func(i, j int) bool {
+		if exported.Plans[i].From != exported.Plans[j].From {
+			return exported.Plans[i].From < exported.Plans[j].From
+		}
+
+		return exported.Plans[i].To < exported.Plans[j].To
+	})
+
+	err := json.NewEncoder(w).Encode(exported)
+	if err != nil {
+		return fmt.Errorf("error in Encode: %w", err)
+	}
+
+	return nil
+}
+
+// ImportPlans reads plans written by ExportPlans. Imported plans are used instead of building
+// new ones if struct types and configuration affecting plans haven't changed since export,
+// e.g. converters, implementations, strategies, variants, clone method and name transformers,
+// otherwise plans are built as usual.
+func (m *Mapper) ImportPlans(r io.Reader) error {
+	var imported exportedPlans
+	err := json.NewDecoder(r).Decode(&imported)
+	if err != nil {
+		return fmt.Errorf("error in Decode: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.importedPlans == nil {
+		m.importedPlans = make(map[importedPlanKey]importedPlan)
+	}
+
+	fingerprint := imported.fingerprint()
+	for _, plan := range imported.Plans {
+		key := importedPlanKey{from: plan.From, to: plan.To, protobuf: plan.Protobuf, graphql: plan.GraphQL}
+		m.importedPlans[key] = importedPlan{exportedPlan: plan, fingerprint: fingerprint}
+	}
+
+	return nil
+}
+
+// importedPlan is a plan with configuration fingerprint of its export.
+type importedPlan struct {
+	exportedPlan
+	fingerprint string
+}
+
+// planConfig returns configuration of mapper affecting plans, except name transformers,
+// which effects are exported with plans, see exportedPlan.Sources.
+func (m *Mapper) planConfig() exportedPlans {
+	config := exportedPlans{
+		Converters:  m.converterNames(),
+		Impls:       m.implNames(),
+		Flattened:   m.flattenedNames(),
+		CloneMethod: m.cloneMethod,
+	}
+	for _, strategy := range m.order {
+		config.Strategies = append(config.Strategies, m.names[strategy])
+	}
+
+	for _, v := range m.variants {
+		config.Variants = append(config.Variants, typeName(v.wrapper)+" "+typeName(v.domain))
+	}
+
+	for tp := range m.moneys {
+		config.Moneys = append(config.Moneys, typeName(tp))
+	}
+
+	sort.Strings(config.Moneys)
+	return config
+}
+
+// fingerprint describes configuration of plans, so that its changes can be detected.
+func (p exportedPlans) fingerprint() string {
+	p.Plans = nil
+	fingerprint, _ := json.Marshal(p)
+	return string(fingerprint)
+}
+
+// sourceNames returns names of source fields matched by destination fields of mappingInfo.
+func (m *Mapper) sourceNames(mappingInfo structMappingInfo) []string {
+	fromFields := mappingInfo.fields(mappingInfo.from)
+	toFields := mappingInfo.fields(mappingInfo.to)
+	names := make([]string, 0, len(toFields.list))
+	for _, meta := range toFields.list {
+		names = append(names, m.sourceName(fromFields, meta.name))
+	}
+
+	return names
+}
+
+func (m *Mapper) exportFields(plan []fieldOp) []exportedField {
+	fields := make([]exportedField, 0, len(plan))
+	for i := range plan {
+		fields = append(fields, m.exportField(&plan[i]))
+	}
+
+	return fields
+}
+
+func (m *Mapper) exportField(op *fieldOp) exportedField {
+	field := exportedField{
+		Name:      op.name,
+		FromIndex: op.fromIndex,
+		ToIndex:   op.toIndex,
+		Getter:    op.getter,
+		FromPath:  op.fromPath,
+		ToPath:    op.toPath,
+		Strategy:  m.names[op.strategy],
+		RemainKey: op.remainKey,
+		NoSource:  op.require && op.fromType == nil,
+	}
+	if op.strategy == converterFunc && op.converter.IsValid() {
+		field.Converter = funcName(op.converter)
+	}
+
+	for _, remain := range op.remain {
+		field.Remain = append(field.Remain, exportedRemain{Index: remain.index, Key: remain.key, Consumed: remain.consumed})
+	}
+
+	for i := range op.oneofs {
+		oneof := m.exportField(&op.oneofs[i].op)
+		oneof.Wrapper = typeName(op.oneofs[i].wrapper)
+		field.Oneofs = append(field.Oneofs, oneof)
+	}
+
+	return field
+}
+
+// restorePlan returns imported plan for mapping if it's still valid.
+func (m *Mapper) restorePlan(mappingInfo structMappingInfo) ([]fieldOp, bool) {
+	// snapshots restore imported plans while they are taken
+	if m.frozen {
+		return nil, false
+	}
+
+	m.mu.RLock()
+	imported, ok := m.importedPlans[importedPlanKey{
+		from:     typeName(mappingInfo.from),
+		to:       typeName(mappingInfo.to),
+		protobuf: mappingInfo.protobuf,
+		graphql:  mappingInfo.graphql,
+	}]
+	m.mu.RUnlock()
+	if !ok ||
+		imported.FromSignature != typeSignature(mappingInfo.from) ||
+		imported.ToSignature != typeSignature(mappingInfo.to) ||
+		strings.Join(imported.Sources, "\n") != strings.Join(m.sourceNames(mappingInfo), "\n") ||
+		imported.fingerprint != m.planConfig().fingerprint() {
+		return nil, false
+	}
+
+	fields := mappingInfo.fields(mappingInfo.to)
+	// plans exported before tags were validated aren't restored, and neither are
+	// plans with named converters, which aren't fingerprinted
+	fromFields := mappingInfo.fields(mappingInfo.from)
+	if fromFields.invalidTag() != nil || fields.invalidTag() != nil || fromFields.usesNamed() || fields.usesNamed() {
+		return nil, false
+	}
+
+	plan := make([]fieldOp, 0, len(imported.Fields))
+	for _, field := range imported.Fields {
+		toIndex, toType, ok := importedTarget(mappingInfo.to, fields, field)
+		if !ok {
+			return nil, false
+		}
+
+		op, ok := m.importField(mappingInfo.from, toType, field)
+		if !ok {
+			return nil, false
+		}
+
+		op.toIndex, op.toPath = toIndex, field.ToPath
+		op.setDestTags(importedMeta(mappingInfo, fields, field))
+		plan = append(plan, op)
+	}
+
+	return plan, true
+}
+
+// importedTarget returns index and type of destination field of imported field.
+func importedTarget(to reflect.Type, fields *structFields, field exportedField) (int, reflect.Type, bool) {
+	if len(field.ToPath) > 0 {
+		toType, ok := typeByPath(to, field.ToPath)
+		return field.ToPath[0], toType, ok
+	}
+
+	meta, ok := fields.field(field.Name)
+	if !ok {
+		return 0, nil, false
+	}
+
+	return meta.index, to.Field(meta.index).Type, true
+}
+
+// importedMeta returns metadata of destination field of imported field found by importedTarget.
+func importedMeta(mappingInfo structMappingInfo, fields *structFields, field exportedField) *fieldMeta {
+	if len(field.ToPath) > 0 {
+		embedded := structType(mappingInfo.to.Field(field.ToPath[0]).Type)
+		return &mappingInfo.fields(embedded).list[field.ToPath[1]]
+	}
+
+	meta, _ := fields.field(field.Name)
+	return meta
+}
+
+func (m *Mapper) importField(from, toType reflect.Type, field exportedField) (fieldOp, bool) {
+	op := fieldOp{
+		name:      field.Name,
+		fromIndex: field.FromIndex,
+		toIndex:   field.ToIndex,
+		getter:    field.Getter,
+		toType:    toType,
+	}
+	switch {
+	case field.NoSource:
+		return op, true
+	case len(field.Remain) > 0:
+		for _, remain := range field.Remain {
+			if remain.Index >= from.NumField() {
+				return fieldOp{}, false
+			}
+
+			op.remain = append(op.remain, remainField{index: remain.Index, key: remain.Key, consumed: remain.Consumed})
+		}
+
+		return op, true
+	case field.RemainKey != "":
+		if op.fromIndex >= from.NumField() || from.Field(op.fromIndex).Type.Kind() != reflect.Map {
+			return fieldOp{}, false
+		}
+
+		op.fromType, op.remainKey, op.mapperFunc = from.Field(op.fromIndex).Type.Elem(), field.RemainKey, m.mapValue
+		return op, true
+	case len(field.Oneofs) > 0:
+		for _, oneof := range field.Oneofs {
+			wrapper, ok := m.impl(oneof.Wrapper)
+			if !ok {
+				return fieldOp{}, false
+			}
+
+			caseOp, ok := m.importField(from, wrapper.Elem().Field(0).Type, oneof)
+			if !ok {
+				return fieldOp{}, false
+			}
+
+			op.oneofs = append(op.oneofs, oneofCase{wrapper: wrapper, op: caseOp})
+		}
+
+		return op, true
+	case len(field.FromPath) > 0:
+		fromType, ok := typeByPath(from, field.FromPath)
+		if !ok {
+			return fieldOp{}, false
+		}
+
+		op.fromType, op.fromPath = fromType, field.FromPath
+	case op.getter >= 0 && op.getter < reflect.PointerTo(from).NumMethod():
+		getter := reflect.PointerTo(from).Method(op.getter)
+		if getter.Name != "Get"+field.Name {
+			return fieldOp{}, false
+		}
+
+		op.fromType = getter.Type.Out(0)
+	case op.getter < 0 && op.fromIndex < from.NumField():
+		op.fromType = from.Field(op.fromIndex).Type
+	default:
+		return fieldOp{}, false
+	}
+
+	// converter ops without registered converters, e.g. of money types, are rebuilt
+	if field.Strategy == m.names[converterFunc] && field.Converter == "" {
+		return fieldOp{}, false
+	}
+
+	for strategy, name := range m.names {
+		if name == field.Strategy {
+			m.bindStrategy(&op, strategy)
+		}
+	}
+
+	if op.strategy == unsupported {
+		op.err = fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, op.fromType, op.toType)
+	}
+
+	return op, op.strategy != unsupported || field.Strategy == m.names[unsupported]
+}
+
+func (m *Mapper) impl(name string) (reflect.Type, bool) {
+	for _, impl := range m.impls {
+		if typeName(impl) == name {
+			return impl, true
+		}
+	}
+
+	return nil, false
+}
+
+// converterNames returns sorted descriptions of registered converters.
+func (m *Mapper) converterNames() []string {
+	converters := m.converterTable()
+	names := make([]string, 0, len(converters))
+	for info, converter := range converters {
+		names = append(names, fmt.Sprintf("%s -> %s: %s", info.from, info.to, funcName(converter)))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (m *Mapper) flattenedNames() []string {
+	names := make([]string, 0, len(m.flattened))
+	for _, tp := range m.flattened {
+		names = append(names, typeName(tp))
+	}
+
+	return names
+}
+
+func (m *Mapper) implNames() []string {
+	names := make([]string, 0, len(m.impls))
+	for _, impl := range m.impls {
+		names = append(names, typeName(impl))
+	}
+
+	return names
+}
+
+func funcName(fn reflect.Value) string {
+	return runtime.FuncForPC(fn.Pointer()).Name()
+}
+
+// typeName returns package qualified name of named type or pointer to it, or empty string.
+func typeName(tp reflect.Type) string {
+	prefix := ""
+	if tp.Kind() == reflect.Ptr {
+		prefix, tp = "*", tp.Elem()
+	}
+
+	if tp.Name() == "" {
+		return ""
+	}
+
+	return prefix + tp.PkgPath() + "." + tp.Name()
+}
+
+// typeSignature describes struct fields, so that changes to them can be detected.
+func typeSignature(tp reflect.Type) string {
+	var signature strings.Builder
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		fmt.Fprintf(&signature, "%s %s %q;", field.Name, field.Type, field.Tag)
+	}
+
+	return signature.String()
+}
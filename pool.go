@@ -0,0 +1,51 @@
+package automapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// statePool holds released *mapState to reuse between calls.
+var statePool = sync.Pool{
+	New: func() interface{} {
+		return newEmptyMapState()
+	},
+}
+
+func newEmptyMapState() *mapState {
+	return &mapState{opts: &options{}, visited: make(map[visitKey]reflect.Value)}
+}
+
+// WithPooling enables or disables reuse of per-call scratch state between Map calls.
+// Pooling is enabled by default, disabling it may help to debug state leaking between calls.
+func WithPooling(enabled bool) Option {
+	return func(o *options) {
+		o.noPooling = !enabled
+	}
+}
+
+func acquireMapState() *mapState {
+	if st, ok := statePool.Get().(*mapState); ok {
+		return st
+	}
+
+	return newEmptyMapState()
+}
+
+// releaseMapState puts state back to the pool, unless pooling is disabled.
+// State must not be used after release.
+func releaseMapState(st *mapState) {
+	if st.opts.noPooling {
+		return
+	}
+
+	*st.opts = options{}
+	st.sels = st.sels[:0]
+	clear(st.visited)
+	st.depth = 0
+	st.ctx = nil
+	st.path = st.path[:0]
+	st.parent = reflect.Value{}
+	st.changes = nil
+	statePool.Put(st)
+}
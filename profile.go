@@ -0,0 +1,34 @@
+package automapper
+
+import "fmt"
+
+// Profile is mapping configuration of a feature, e.g. converters and pairs of a package,
+// which is registered into mappers with AddProfile.
+type Profile interface {
+	// Configure registers configuration into m, returning the first registration error.
+	Configure(m *Mapper) error
+}
+
+// ProfileFunc is a func used as Profile.
+type ProfileFunc func(m *Mapper) error
+
+// Configure calls f.
+func (f ProfileFunc) Configure(m *Mapper) error {
+	return f(m)
+}
+
+// AddProfile configures m with profiles in order, stopping on the first failing one,
+// which error is prefixed with its type.
+func (m *Mapper) AddProfile(profiles ...Profile) error {
+	for _, profile := range profiles {
+		if profile == nil {
+			return fmt.Errorf("%w: profile", ErrNilValue)
+		}
+
+		if err := profile.Configure(m); err != nil {
+			return fmt.Errorf("profile %T: %w", profile, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package automapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithProtobuf enables protobuf-generated struct awareness:
+//   - protobuf internal fields (state, sizeCache, unknownFields, XXX_*) are skipped;
+//   - source values are read via getters (GetName) when source has them, so optional
+//     fields are dereferenced and oneof cases can be mapped into plain destination fields;
+//   - oneof destination fields are populated with wrappers registered with RegisterImplementations.
+func WithProtobuf() Option {
+	return func(o *options) {
+		o.protobuf = true
+	}
+}
+
+// RegisterImplementations registers concrete types used to populate interface destination fields,
+// e.g. protobuf oneof wrappers:
+//
+//	m.RegisterImplementations(&pb.Event_Created{}, &pb.Event_Deleted{})
+//
+// Structs mapped into interface destinations, e.g. elements of []any, are mapped into
+// the implementation having most fields in common with them.
+func (m *Mapper) RegisterImplementations(impls ...interface{}) {
+	for _, impl := range impls {
+		m.impls = append(m.impls, reflect.TypeOf(impl))
+	}
+
+	m.resetPlans()
+}
+
+func isProtobufInternalField(name string) bool {
+	switch name {
+	case "state", "sizeCache", "unknownFields":
+		return true
+	default:
+		return strings.HasPrefix(name, "XXX_")
+	}
+}
+
+func protobufGetter(from reflect.Value, name string) (reflect.Value, bool) {
+	if from.CanAddr() {
+		from = from.Addr()
+	}
+
+	getter := from.MethodByName("Get" + name)
+	if !getter.IsValid() || getter.Type().NumIn() != 0 || getter.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+
+	return getter, true
+}
+
+// protobufGetterMethod returns getter of source field with name in protobuf mode.
+func protobufGetterMethod(mappingInfo structMappingInfo, name string) (reflect.Method, bool) {
+	if !mappingInfo.protobuf {
+		return reflect.Method{}, false
+	}
+
+	getter, ok := reflect.PointerTo(mappingInfo.from).MethodByName("Get" + name)
+	if !ok || getter.Type.NumIn() != 1 || getter.Type.NumOut() != 1 {
+		return reflect.Method{}, false
+	}
+
+	return getter, true
+}
+
+// oneofCases returns registered wrapper types implementing interface destination
+// which single field can be mapped from source field with the same name.
+func (m *Mapper) oneofCases(mappingInfo structMappingInfo, fromFields *structFields, to reflect.Type) []oneofCase {
+	var cases []oneofCase
+	for _, impl := range m.impls {
+		if !impl.Implements(to) || !isOneofWrapper(impl) {
+			continue
+		}
+
+		caseField := impl.Elem().Field(0)
+		op, ok := m.newFieldOp(mappingInfo, fromFields, caseField.Name, caseField.Type, nil)
+		if ok && op.err == nil {
+			cases = append(cases, oneofCase{wrapper: impl, op: op})
+		}
+	}
+
+	return cases
+}
+
+// mapOneof populates interface destination with the first wrapper which source field is not zero.
+func mapOneof(st *mapState, cases []oneofCase, from, to reflect.Value) error {
+	for i := range cases {
+		fromVal := cases[i].op.source(from)
+		if fromVal.IsZero() {
+			continue
+		}
+
+		wrapper := reflect.New(cases[i].wrapper.Elem())
+		err := cases[i].op.mapperFunc(st, fromVal, wrapper.Elem().Field(0))
+		if err != nil {
+			return err
+		}
+
+		to.Set(wrapper)
+		return nil
+	}
+
+	return nil
+}
+
+// isOneofWrapper reports whether tp is a pointer to struct with a single exported field.
+func isOneofWrapper(tp reflect.Type) bool {
+	return tp.Kind() == reflect.Ptr && tp.Elem().Kind() == reflect.Struct &&
+		tp.Elem().NumField() == 1 && tp.Elem().Field(0).PkgPath == ""
+}
@@ -0,0 +1,123 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// isRecord reports whether tp is a CSV record type, i.e. []string.
+func isRecord(tp reflect.Type) bool {
+	return tp.Kind() == reflect.Slice && tp.Elem().Kind() == reflect.String
+}
+
+// hasRecordFields reports whether struct has fields tagged with record index, e.g. `mapper:"csv=3"`.
+func hasRecordFields(tp reflect.Type) bool {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	for _, meta := range cachedFields(tp).list {
+		if _, ok := meta.optionValue(tagCSV); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordIndex returns record index of field tagged with `mapper:"csv=3"`.
+func recordIndex(meta *fieldMeta) (int, bool, error) {
+	value, ok := meta.optionValue(tagCSV)
+	if !ok {
+		return 0, false, nil
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return 0, false, fmt.Errorf("%w '%s' of field %s", ErrInvalidTag, value, meta.name)
+	}
+
+	return index, true, nil
+}
+
+// mapFromRecordFunc maps []string record into struct fields tagged with record indexes,
+// parsing values with registered converters. Missing trailing columns are skipped.
+func (m *Mapper) mapFromRecordFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if toVal.Kind() == reflect.Ptr {
+		toVal.Set(m.allocate(st, toVal.Type().Elem()))
+		toVal = toVal.Elem()
+	}
+
+	fields := cachedFields(toVal.Type())
+	for i := range fields.list {
+		meta := &fields.list[i]
+		index, ok, err := recordIndex(meta)
+		if err != nil {
+			return withFieldPath(err, meta.name)
+		}
+
+		if !ok || index >= fromVal.Len() || !toVal.Field(meta.index).CanSet() {
+			continue
+		}
+
+		if _, ok = st.field(meta.name); !ok {
+			continue
+		}
+
+		err = m.setString(st, fromVal.Index(index).String(), toVal.Field(meta.index))
+		if err != nil {
+			return withFieldPath(err, meta.name)
+		}
+	}
+
+	return nil
+}
+
+// mapToRecordFunc maps struct fields tagged with record indexes into []string record,
+// formatting values with registered converters.
+func (m *Mapper) mapToRecordFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if fromVal.Kind() == reflect.Ptr {
+		fromVal = fromVal.Elem()
+	}
+
+	record := make(map[int]string)
+	length := 0
+	fields := cachedFields(fromVal.Type())
+	for i := range fields.list {
+		meta := &fields.list[i]
+		index, ok, err := recordIndex(meta)
+		if err != nil {
+			return withFieldPath(err, meta.name)
+		}
+
+		if !ok || !meta.exported {
+			continue
+		}
+
+		if _, ok = st.field(meta.name); !ok {
+			continue
+		}
+
+		values, err := m.formatStrings(st, fromVal.Field(meta.index))
+		if err != nil {
+			return withFieldPath(err, meta.name)
+		}
+
+		if len(values) > 0 {
+			record[index] = values[0]
+		}
+
+		if index >= length {
+			length = index + 1
+		}
+	}
+
+	slice := reflect.MakeSlice(toVal.Type(), length, length)
+	for index, value := range record {
+		slice.Index(index).SetString(value)
+	}
+
+	toVal.Set(slice)
+	return nil
+}
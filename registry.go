@@ -0,0 +1,106 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// converterTable holds converters by their types. Tables are never modified after they are stored,
+// registration stores a copy, so that Map reads converters without locks while Set is called.
+type converterTable map[converterInfo]reflect.Value
+
+// validateConverter checks that fn is a type of converter in one of the forms Set accepts.
+func validateConverter(fn reflect.Type) error {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return ErrNotAFn
+	}
+
+	if fn.IsVariadic() || fn.NumIn() != 1 || fn.NumOut() == 0 || fn.NumOut() > 2 {
+		return fmt.Errorf("%w '%s'", ErrBadConverterSignature, fn)
+	}
+
+	if fn.NumOut() == 2 && !fn.Out(1).Implements(errorType) {
+		return fmt.Errorf("%w '%s': %w", ErrBadConverterSignature, fn, ErrConverterErrorUnknownType)
+	}
+
+	return nil
+}
+
+// converterTable returns current converters, which must not be modified.
+func (m *Mapper) converterTable() converterTable {
+	table := m.converters.Load()
+	if table == nil {
+		return nil
+	}
+
+	return *table
+}
+
+// storeConverter registers converter for info types, replacing the current table with its copy.
+func (m *Mapper) storeConverter(info converterInfo, converter reflect.Value) {
+	m.convertersMu.Lock()
+	defer m.convertersMu.Unlock()
+	current := m.converterTable()
+	table := make(converterTable, len(current)+1)
+	for key, value := range current {
+		table[key] = value
+	}
+
+	table[info] = converter
+	m.converters.Store(&table)
+}
+
+// converterShape tells how types of converter differ from types it maps.
+type converterShape int
+
+const (
+	exactShape converterShape = iota
+	// pointerArgShape converter accepts pointer to mapped value, e.g. func(*Money) string maps Money into string.
+	pointerArgShape
+	// pointerResultShape converter returns pointer to mapped value, e.g. func(int) *string maps int into string.
+	pointerResultShape
+)
+
+// lookup returns converter mapping from type into to type, converters of exact types win over
+// converters accepting or returning pointers. Converters of pointers aren't used for identical types,
+// so that they are still copied.
+func (t converterTable) lookup(from, to reflect.Type) (reflect.Value, converterShape, bool) {
+	if converter, ok := t[converterInfo{from: from, to: to}]; ok || len(t) == 0 || from == to {
+		return converter, exactShape, ok
+	}
+
+	if from.Kind() != reflect.Ptr {
+		if converter, ok := t[converterInfo{from: reflect.PointerTo(from), to: to}]; ok {
+			return converter, pointerArgShape, true
+		}
+	}
+
+	if to.Kind() != reflect.Ptr {
+		if converter, ok := t[converterInfo{from: from, to: reflect.PointerTo(to)}]; ok {
+			return converter, pointerResultShape, true
+		}
+	}
+
+	return reflect.Value{}, exactShape, false
+}
+
+// callShaped calls converter of shape, passing pointer to copy of fromVal to converters accepting pointers,
+// and setting values pointed by results of converters returning pointers, nil results leave toVal unchanged.
+func (m *Mapper) callShaped(converter reflect.Value, shape converterShape, fromVal, toVal reflect.Value) error {
+	switch shape {
+	case pointerArgShape:
+		ptr := reflect.New(fromVal.Type())
+		ptr.Elem().Set(fromVal)
+		return m.callConverter(converter, ptr, toVal)
+	case pointerResultShape:
+		ptr := reflect.New(reflect.PointerTo(toVal.Type())).Elem()
+		err := m.callConverter(converter, fromVal, ptr)
+		if !ptr.IsNil() {
+			toVal.Set(ptr.Elem())
+		}
+
+		return err
+	default:
+		return m.callConverter(converter, fromVal, toVal)
+	}
+}
@@ -0,0 +1,191 @@
+package automapper
+
+import "reflect"
+
+// remainOption is a tag option of destination map field collecting source fields
+// without destination fields, e.g. `mapper:",remain"` of map[string]any field.
+// Entries of source remain map fill destination fields without source fields.
+const remainOption = "remain"
+
+// remainField is a source field collected into remain map by key.
+// Entries of source remain map are merged, except consumed ones, if key is empty.
+type remainField struct {
+	index    int
+	key      string
+	consumed []string
+}
+
+// isRemainField reports whether destination field collects unmatched source fields.
+func isRemainField(meta *fieldMeta, tp reflect.Type) bool {
+	return meta.hasOption(remainOption) && tp.Kind() == reflect.Map && tp.Key().Kind() == reflect.String
+}
+
+// remainFields returns exported source fields, which are not read by plan.
+func remainFields(mappingInfo structMappingInfo, fromFields *structFields, plan []fieldOp) []remainField {
+	used := make(map[int]bool, len(plan))
+	for i := range plan {
+		switch {
+		case len(plan[i].fromPath) > 0:
+			used[plan[i].fromPath[0]] = true
+		case plan[i].getter < 0 && len(plan[i].oneofs) == 0:
+			used[plan[i].fromIndex] = true
+		}
+	}
+
+	var fields []remainField
+	for _, meta := range fromFields.list {
+		if !meta.exported || meta.hasOption(tagIgnore) || (mappingInfo.protobuf && isProtobufInternalField(meta.name)) {
+			continue
+		}
+
+		if isRemainField(&meta, mappingInfo.from.Field(meta.index).Type) {
+			fields = append(fields, remainField{index: meta.index, consumed: consumedKeys(plan, meta.index)})
+			continue
+		}
+
+		if !used[meta.index] {
+			fields = append(fields, remainField{index: meta.index, key: meta.name})
+		}
+	}
+
+	return fields
+}
+
+// sourceRemain returns source remain field, which entries fill destination fields without source fields.
+func sourceRemain(mappingInfo structMappingInfo, fromFields *structFields) (*fieldMeta, bool) {
+	for i := range fromFields.list {
+		meta := &fromFields.list[i]
+		if meta.exported && isRemainField(meta, mappingInfo.from.Field(meta.index).Type) {
+			return meta, true
+		}
+	}
+
+	return nil, false
+}
+
+// remainKeyOp returns op mapping entry of source remain map with key into destination field of toType.
+func (m *Mapper) remainKeyOp(mappingInfo structMappingInfo, remain *fieldMeta, key string, toType reflect.Type) fieldOp {
+	return fieldOp{
+		name:       key,
+		fromIndex:  remain.index,
+		fromType:   mappingInfo.from.Field(remain.index).Type.Elem(),
+		toType:     toType,
+		getter:     -1,
+		remainKey:  key,
+		mapperFunc: m.mapValue,
+	}
+}
+
+// consumedKeys returns keys of entries of source remain map at index, which plan maps into fields.
+func consumedKeys(plan []fieldOp, index int) []string {
+	var keys []string
+	for i := range plan {
+		if plan[i].remainKey != "" && plan[i].fromIndex == index {
+			keys = append(keys, plan[i].remainKey)
+		}
+	}
+
+	return keys
+}
+
+// mapRemain sets non-zero source fields into remain map to by their names.
+// Values not assignable to map values are mapped into them.
+// Entries of source remain maps are merged.
+func (m *Mapper) mapRemain(st *mapState, fields []remainField, from, to reflect.Value) error {
+	elemType := to.Type().Elem()
+	for _, field := range fields {
+		value := from.Field(field.index)
+		if value.IsZero() {
+			continue
+		}
+
+		if field.key == "" {
+			err := m.mergeRemain(st, field.consumed, value, to)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, err := m.remainEntry(st, value, elemType)
+		if err != nil {
+			return withFieldPath(err, "["+field.key+"]")
+		}
+
+		if to.IsNil() {
+			to.Set(reflect.MakeMap(to.Type()))
+		}
+
+		to.SetMapIndex(reflect.ValueOf(field.key).Convert(to.Type().Key()), value)
+	}
+
+	return nil
+}
+
+// mergeRemain sets entries of source remain map from into remain map to, except consumed ones.
+func (m *Mapper) mergeRemain(st *mapState, consumed []string, from, to reflect.Value) error {
+	for _, k := range sortedKeys(from) {
+		key := k.String()
+		if containsKey(consumed, key) {
+			continue
+		}
+
+		value := from.MapIndex(k)
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+
+		if !value.IsValid() {
+			continue
+		}
+
+		elem, err := m.remainEntry(st, value, to.Type().Elem())
+		if err != nil {
+			return withFieldPath(err, "["+key+"]")
+		}
+
+		if to.IsNil() {
+			to.Set(reflect.MakeMap(to.Type()))
+		}
+
+		to.SetMapIndex(reflect.ValueOf(key).Convert(to.Type().Key()), elem)
+	}
+
+	return nil
+}
+
+// remainEntry returns value as remain map value of elemType, mapping it if it's not assignable.
+func (m *Mapper) remainEntry(st *mapState, value reflect.Value, elemType reflect.Type) (reflect.Value, error) {
+	if value.Type().AssignableTo(elemType) {
+		return value, nil
+	}
+
+	elem := reflect.New(elemType).Elem()
+	err := m.mapValue(st, value, elem)
+	return elem, err
+}
+
+// remainValue returns entry of source remain map with key, unwrapping interface values.
+func remainValue(remain reflect.Value, key string, tp reflect.Type) reflect.Value {
+	value := remain.MapIndex(reflect.ValueOf(key).Convert(remain.Type().Key()))
+	if value.IsValid() && value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return reflect.Zero(tp)
+	}
+
+	return value
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,26 @@
+package automapper
+
+import "fmt"
+
+// MissingSourceError is returned when source field of destination field tagged with
+// `mapper:",require"` is missing or zero, e.g. when upstream service omits it.
+type MissingSourceError struct {
+	// Path is a path of destination field, e.g. "Customer.Email".
+	Path string
+}
+
+func (e *MissingSourceError) Error() string {
+	return fmt.Sprintf("%s: field %s", ErrMissingSource, e.Path)
+}
+
+func (e *MissingSourceError) Unwrap() error {
+	return ErrMissingSource
+}
+
+func (e *MissingSourceError) prependPath(segment string) {
+	e.Path = joinPath(segment, e.Path)
+}
+
+func (e *MissingSourceError) fieldPath() string {
+	return e.Path
+}
@@ -0,0 +1,129 @@
+package automapper
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// ScanRows scans every row of rows into the slice pointed by to, which element must be
+// a struct or a pointer to struct. Columns match destination fields the same way MapMasked paths do,
+// so column user_name fills field UserName or field tagged with `mapper:"user_name"`.
+// Columns are scanned directly into fields, unless converter to field type is registered with Set:
+// then column value is scanned as is and passed to the converter ([]byte values are passed as strings).
+// Columns without matching fields are discarded. ScanRows doesn't close rows.
+// Errors are *Error like errors of Map.
+func (m *Mapper) ScanRows(rows *sql.Rows, to interface{}, opts ...Option) error {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	return m.translateError(m.scanRows(st, rows, to))
+}
+
+func (m *Mapper) scanRows(st *mapState, rows *sql.Rows, to interface{}) error {
+	if err := st.opts.err(); err != nil {
+		return err
+	}
+
+	valTo := reflect.ValueOf(to)
+	if valTo.Kind() != reflect.Ptr || valTo.Elem().Kind() != reflect.Slice || !isStructOrPtrToStruct(valTo.Elem().Type().Elem()) {
+		return ErrNotAPtr
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("error in Columns: %w", err)
+	}
+
+	slice := valTo.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	for rows.Next() {
+		elem := m.newValue(structType)
+		err = m.scanRow(st, rows, columns, elem.Elem())
+		if err != nil {
+			return err
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error in Next: %w", err)
+	}
+
+	valTo.Elem().Set(slice)
+	return nil
+}
+
+// convertedColumn is a column scanned as is to be converted into field.
+type convertedColumn struct {
+	value *interface{}
+	to    reflect.Value
+}
+
+func (m *Mapper) scanRow(st *mapState, rows *sql.Rows, columns []string, to reflect.Value) error {
+	dest := make([]interface{}, len(columns))
+	var converted []convertedColumn
+	for i, column := range columns {
+		toVal, ok := destFieldByKey(st, to, column)
+		switch {
+		case !ok:
+			dest[i] = new(interface{})
+		case m.hasConverterTo(toVal.Type()):
+			conv := convertedColumn{value: new(interface{}), to: toVal}
+			converted = append(converted, conv)
+			dest[i] = conv.value
+		default:
+			dest[i] = toVal.Addr().Interface()
+		}
+	}
+
+	err := rows.Scan(dest...)
+	if err != nil {
+		return fmt.Errorf("error in Scan: %w", err)
+	}
+
+	for _, column := range converted {
+		value, toVal := *column.value, column.to
+		if value == nil {
+			continue
+		}
+
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+
+		fromVal := reflect.ValueOf(value)
+		mappingType := m.detectMappingType(fromVal.Type(), toVal.Type())
+		if mappingType == unsupported {
+			err = m.missingConverter(fromVal, toVal)
+		} else {
+			err = m.strats[mappingType](st, fromVal, toVal)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasConverterTo reports whether any converter to tp is registered.
+func (m *Mapper) hasConverterTo(tp reflect.Type) bool {
+	for info := range m.converterTable() {
+		if info.to == tp {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package automapper_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lebedevars/automapper"
+)
+
+// rowsDriver serves rows of the package-level table for any query.
+type rowsDriver struct{}
+
+type rowsConn struct{}
+
+type rowsStmt struct{}
+
+type driverRows struct {
+	i int
+}
+
+var tableColumns = []string{"id", "user_name", "status", "unknown"}
+
+var tableRows = [][]driver.Value{
+	{int64(1), "first", []byte("active"), "x"},
+	{int64(2), nil, []byte("blocked"), "y"},
+}
+
+func init() {
+	sql.Register("automapper_rows", rowsDriver{})
+}
+
+func (rowsDriver) Open(string) (driver.Conn, error) { return rowsConn{}, nil }
+
+func (rowsConn) Prepare(string) (driver.Stmt, error) { return rowsStmt{}, nil }
+func (rowsConn) Close() error                        { return nil }
+func (rowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (rowsStmt) Close() error                               { return nil }
+func (rowsStmt) NumInput() int                              { return 0 }
+func (rowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (rowsStmt) Query([]driver.Value) (driver.Rows, error)  { return &driverRows{}, nil }
+
+func (*driverRows) Columns() []string { return tableColumns }
+func (*driverRows) Close() error      { return nil }
+
+func (r *driverRows) Next(dest []driver.Value) error {
+	if r.i >= len(tableRows) {
+		return io.EOF
+	}
+
+	copy(dest, tableRows[r.i])
+	r.i++
+	return nil
+}
+
+type Status int
+
+type User struct {
+	ID     int
+	Name   *string `mapper:"user_name"`
+	Status Status
+}
+
+func TestMapper_ScanRows(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("automapper_rows", "")
+	assert.NoError(t, err)
+	defer db.Close()
+	rows, err := db.Query("select")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	m := automapper.New()
+	assert.NoError(t, m.Set(func(s string) Status {
+		if s == "active" {
+			return 1
+		}
+		return 2
+	}))
+	var users []*User
+	err = m.ScanRows(rows, &users)
+
+	assert.NoError(t, err)
+	name := "first"
+	assert.EqualValues(t, []*User{
+		{ID: 1, Name: &name, Status: 1},
+		{ID: 2, Status: 2},
+	}, users)
+}
+
+func TestMapper_ScanRows_ErrorCode(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("automapper_rows", "")
+	assert.NoError(t, err)
+	defer db.Close()
+	rows, err := db.Query("select")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var users []User
+	err = automapper.New().ScanRows(rows, users)
+	assert.ErrorIs(t, err, automapper.ErrNotAPtr)
+	var mapErr *automapper.Error
+	if assert.True(t, errors.As(err, &mapErr)) {
+		assert.Equal(t, automapper.CodeUnsupportedType, mapErr.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package automapper
+
+import "reflect"
+
+// WithConverters layers converters over converters of the mapper for a call, e.g. to format times
+// as epoch for a single endpoint. Converters have the forms Set accepts and take precedence over
+// registered converters and strategies of their types, and are applied to elements of slices, arrays and maps
+// the same way registered ones are, including elements held by pointers, e.g. []*A and []B.
+// Invalid converters fail mapping with errors Set returns for them, e.g. ErrNotAFn.
+func WithConverters(converters ...interface{}) Option {
+	return func(o *options) {
+		table := make(converterTable, len(o.converters)+len(converters))
+		for info, converter := range o.converters {
+			table[info] = converter
+		}
+
+		for _, converter := range converters {
+			fn := reflect.TypeOf(converter)
+			if err := validateConverter(fn); err != nil {
+				o.converterErr = err
+				continue
+			}
+
+			table[converterInfo{from: fn.In(0), to: fn.Out(0)}] = reflect.ValueOf(converter)
+		}
+
+		o.converters = table
+	}
+}
+
+// scopedConverter returns converter of the call from type into to type and its shape, see WithConverters.
+func scopedConverter(st *mapState, from, to reflect.Type) (reflect.Value, converterShape, bool) {
+	if len(st.opts.converters) == 0 {
+		return reflect.Value{}, exactShape, false
+	}
+
+	return st.opts.converters.lookup(from, to)
+}
+
+// scopedMapper returns mapper func calling converter of the call from type into to type, nil if there's none.
+func (m *Mapper) scopedMapper(st *mapState, from, to reflect.Type) mapperFunc {
+	converter, shape, ok := scopedConverter(st, from, to)
+	if !ok {
+		return nil
+	}
+
+	return func(st *mapState, fromVal, toVal reflect.Value) error {
+		return m.callShaped(converter, shape, fromVal, toVal)
+	}
+}
+
+// liftedScoped returns collection or pointers strategy mapping from type into to type, which elements are mapped
+// with converters of the call, nil if converters of the call don't map them, e.g. []*A into []B. Strategies map
+// elements with mapValue, which applies converters of the call first.
+func (m *Mapper) liftedScoped(st *mapState, from, to reflect.Type) mapperFunc {
+	if len(st.opts.converters) == 0 {
+		return nil
+	}
+
+	switch {
+	case from.Kind() == reflect.Slice && to.Kind() == reflect.Slice && m.mapsScoped(st, from.Elem(), to.Elem()):
+		return m.mapSlicesFunc
+	case from.Kind() == reflect.Array && to.Kind() == reflect.Array && from.Len() == to.Len() &&
+		m.mapsScoped(st, from.Elem(), to.Elem()):
+		return m.mapArraysFunc
+	case from.Kind() == reflect.Map && to.Kind() == reflect.Map &&
+		m.mapsScoped(st, from.Key(), to.Key()) && m.mapsScoped(st, from.Elem(), to.Elem()):
+		return m.mapMapsFunc
+	case from.Kind() == reflect.Ptr || to.Kind() == reflect.Ptr:
+		// pointers are unwrapped and allocated around converted values like pointers strategy does
+		fromElem, toElem := from, to
+		for fromElem.Kind() == reflect.Ptr {
+			fromElem = fromElem.Elem()
+		}
+
+		if toElem.Kind() == reflect.Ptr {
+			toElem = toElem.Elem()
+		}
+
+		if m.mapsScoped(st, fromElem, toElem) {
+			return m.mapPointersFunc
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// mapsScoped reports whether from type is mapped into to type with converters of the call or strategies.
+func (m *Mapper) mapsScoped(st *mapState, from, to reflect.Type) bool {
+	if _, _, ok := scopedConverter(st, from, to); ok {
+		return true
+	}
+
+	return m.detectMappingType(from, to) != unsupported || m.liftedScoped(st, from, to) != nil
+}
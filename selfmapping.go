@@ -0,0 +1,68 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapperTo is implemented by types which map themselves into destination.
+// Fields of such types are mapped with MapTo instead of automatic mapping,
+// registered converters still take precedence.
+type MapperTo interface {
+	// MapTo maps receiver into dst, which is a pointer to destination field.
+	MapTo(dst interface{}) error
+}
+
+// MapperFrom is implemented by types which map source into themselves.
+// It's used like MapperTo when source type doesn't implement MapperTo.
+type MapperFrom interface {
+	// MapFrom maps src, which is a source field value, into receiver.
+	MapFrom(src interface{}) error
+}
+
+var (
+	mapperToType   = reflect.TypeOf((*MapperTo)(nil)).Elem()
+	mapperFromType = reflect.TypeOf((*MapperFrom)(nil)).Elem()
+)
+
+func isSelfMapping(fromType, toType reflect.Type) bool {
+	return fromType.Implements(mapperToType) || reflect.PointerTo(fromType).Implements(mapperToType) ||
+		reflect.PointerTo(toType).Implements(mapperFromType)
+}
+
+func (m *Mapper) mapSelfFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if !toVal.CanAddr() {
+		return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.Type(), toVal.Type())
+	}
+
+	var err error
+	switch {
+	case fromVal.Type().Implements(mapperToType) || reflect.PointerTo(fromVal.Type()).Implements(mapperToType):
+		if !fromVal.CanAddr() {
+			// MapTo may have pointer receiver
+			ptr := reflect.New(fromVal.Type())
+			ptr.Elem().Set(fromVal)
+			fromVal = ptr.Elem()
+		}
+
+		mapperTo, ok := fromVal.Addr().Interface().(MapperTo)
+		if !ok {
+			return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.Type(), toVal.Type())
+		}
+
+		err = mapperTo.MapTo(toVal.Addr().Interface())
+	default:
+		mapperFrom, ok := toVal.Addr().Interface().(MapperFrom)
+		if !ok {
+			return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.Type(), toVal.Type())
+		}
+
+		err = mapperFrom.MapFrom(fromVal.Interface())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConverter, err)
+	}
+
+	return nil
+}
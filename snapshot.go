@@ -0,0 +1,100 @@
+package automapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// Snapshot is a read-only view of Mapper configuration taken by Mapper.Snapshot.
+// Plans of struct pairs mapped or declared before the snapshot, and of structs nested in them,
+// are built in advance, so mapping takes no locks. Plans of other pairs are built on every use.
+// Changes of the Mapper after the snapshot don't affect it.
+type Snapshot struct {
+	m *Mapper
+}
+
+// Snapshot returns read-only view of the Mapper for latency-critical paths,
+// while the Mapper remains configurable.
+func (m *Mapper) Snapshot() *Snapshot {
+	m.mu.RLock()
+	pairs := append([]structMappingInfo(nil), m.pairs...)
+	for mappingInfo := range m.knownMappings {
+		pairs = append(pairs, mappingInfo)
+	}
+
+	imported := make(map[importedPlanKey]importedPlan, len(m.importedPlans))
+	for key, plan := range m.importedPlans {
+		imported[key] = plan
+	}
+	m.mu.RUnlock()
+
+	snap := &Mapper{
+		knownMappings: make(map[structMappingInfo][]fieldOp, len(pairs)),
+		importedPlans: imported,
+		order:         append([]supportedType(nil), m.order...),
+		names:         make(map[supportedType]string, len(m.names)),
+		impls:         append([]reflect.Type(nil), m.impls...),
+		variants:      append([]variant(nil), m.variants...),
+		flattened:     append([]reflect.Type(nil), m.flattened...),
+		fallback:      m.fallback,
+		translate:     m.translate,
+		cloneMethod:   m.cloneMethod,
+		factories:     make(map[reflect.Type]reflect.Value, len(m.factories)),
+		named:         make(map[string]reflect.Value, len(m.named)),
+		moneys:        make(map[reflect.Type]moneyCodec, len(m.moneys)),
+		pools:         make(map[reflect.Type]Pool, len(m.pools)),
+		pooledTypes:   m.pooledTypes,
+		allocHook:     m.allocHook,
+	}
+	m.opts.copyTo(&snap.opts)
+	// every plan is kept
+	snap.opts.planCacheSize = 0
+	snap.converters.Store(m.converters.Load())
+	for tp, factory := range m.factories {
+		snap.factories[tp] = factory
+	}
+
+	for tp, codec := range m.moneys {
+		snap.moneys[tp] = codec
+	}
+
+	for name, converter := range m.named {
+		snap.named[name] = converter
+	}
+
+	for tp, pool := range m.pools {
+		snap.pools[tp] = pool
+	}
+
+	// built-in strategies are bound to their mapper, custom ones are not
+	snap.strats, snap.matchers = snap.initStrategies(), snap.initMatchers()
+	for strategy, name := range m.names {
+		snap.names[strategy] = name
+		if strategy >= customStrategies {
+			snap.strats[strategy], snap.matchers[strategy] = m.strats[strategy], m.matchers[strategy]
+		}
+	}
+
+	for _, pair := range pairs {
+		// errors are returned by mapping of failing fields
+		_ = snap.buildPlans(pair)
+	}
+
+	snap.frozen = true
+	return &Snapshot{m: snap}
+}
+
+// Map maps like Mapper.Map.
+func (s *Snapshot) Map(from, to interface{}, opts ...Option) error {
+	return s.m.Map(from, to, opts...)
+}
+
+// MapCtx maps like Mapper.MapCtx.
+func (s *Snapshot) MapCtx(ctx context.Context, from, to interface{}, opts ...Option) error {
+	return s.m.MapCtx(ctx, from, to, opts...)
+}
+
+// MapMasked maps like Mapper.MapMasked.
+func (s *Snapshot) MapMasked(from, to interface{}, mask []string, opts ...Option) error {
+	return s.m.MapMasked(from, to, mask, opts...)
+}
@@ -0,0 +1,36 @@
+package automapper
+
+import "sync/atomic"
+
+// Stats are counters of Mapper since its creation.
+type Stats struct {
+	// PlanHits and PlanMisses count lookups of struct mapping plans in cache,
+	// misses build new plans.
+	PlanHits, PlanMisses uint64
+	// Plans is a number of cached plans, PlanEvictions counts plans evicted from bounded cache.
+	Plans         int
+	PlanEvictions uint64
+	// ConverterCalls counts calls of converters registered with Set.
+	ConverterCalls uint64
+}
+
+// stats are updated atomically by concurrent Map calls.
+type stats struct {
+	planHits, planMisses, planEvictions, converterCalls atomic.Uint64
+}
+
+// Stats returns counters of plan cache use and converter calls, e.g. to check
+// that plans are reused by long-running services. Stats are published with expvar
+// by package automapperexpvar.
+func (m *Mapper) Stats() Stats {
+	m.mu.RLock()
+	plans := len(m.knownMappings)
+	m.mu.RUnlock()
+	return Stats{
+		PlanHits:       m.stats.planHits.Load(),
+		PlanMisses:     m.stats.planMisses.Load(),
+		Plans:          plans,
+		PlanEvictions:  m.stats.planEvictions.Load(),
+		ConverterCalls: m.stats.converterCalls.Load(),
+	}
+}
@@ -4,6 +4,8 @@ package automapper
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 )
 
 type supportedType int
@@ -15,9 +17,87 @@ const (
 	arrays
 	sameTypes
 	converterFunc
+	fromRecords
+	toRecords
+	selfMapping
+	maps
+	pointers
+	interfaces
+	unixSeconds
+	unixMillis
+	jsonNumbers
+	unions
+	clones
+	atomics
+	// custom strategies registered with RegisterStrategy are numbered from here
+	customStrategies
 )
 
-type mapperFunc func(from, to reflect.Value) error
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isNilError reports whether err, an error result of converter, is nil.
+// Error types of other kinds than pointers, interfaces, maps, slices, funcs and channels are never nil.
+func isNilError(err reflect.Value) bool {
+	switch err.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return err.IsNil()
+	default:
+		return false
+	}
+}
+
+// Names of built-in strategies, see SetPrecedence.
+const (
+	StrategyStructs     = "structs"
+	StrategySlices      = "slices"
+	StrategyArrays      = "arrays"
+	StrategySameTypes   = "sameTypes"
+	StrategyConverter   = "converterFunc"
+	StrategyFromRecords = "fromRecords"
+	StrategyToRecords   = "toRecords"
+	StrategySelfMapping = "selfMapping"
+	StrategyMaps        = "maps"
+	StrategyPointers    = "pointers"
+	StrategyInterfaces  = "interfaces"
+	StrategyUnixSeconds = "unixSeconds"
+	StrategyUnixMillis  = "unixMillis"
+	StrategyJSONNumbers = "jsonNumbers"
+	StrategyUnions      = "unions"
+	StrategyClones      = "clones"
+	StrategyAtomics     = "atomics"
+)
+
+// strategyNames are used to export plans.
+var strategyNames = map[supportedType]string{
+	unsupported:   "unsupported",
+	structs:       StrategyStructs,
+	slices:        StrategySlices,
+	arrays:        StrategyArrays,
+	sameTypes:     StrategySameTypes,
+	converterFunc: StrategyConverter,
+	fromRecords:   StrategyFromRecords,
+	toRecords:     StrategyToRecords,
+	selfMapping:   StrategySelfMapping,
+	maps:          StrategyMaps,
+	pointers:      StrategyPointers,
+	interfaces:    StrategyInterfaces,
+	unixSeconds:   StrategyUnixSeconds,
+	unixMillis:    StrategyUnixMillis,
+	jsonNumbers:   StrategyJSONNumbers,
+	unions:        StrategyUnions,
+	clones:        StrategyClones,
+	atomics:       StrategyAtomics,
+}
+
+type mapperFunc func(st *mapState, from, to reflect.Value) error
+
+// matchFunc reports whether strategy maps fromType into toType.
+type matchFunc func(fromType, toType reflect.Type) bool
+
+// builtinOrder is precedence of built-in strategies, the first matching one is used.
+var builtinOrder = []supportedType{
+	converterFunc, selfMapping, clones, sameTypes, unions, atomics, structs, slices, arrays, maps, fromRecords, toRecords, pointers, interfaces, jsonNumbers,
+}
 
 func (m *Mapper) initStrategies() map[supportedType]mapperFunc {
 	strats := make(map[supportedType]mapperFunc)
@@ -25,44 +105,116 @@ func (m *Mapper) initStrategies() map[supportedType]mapperFunc {
 	strats[slices] = m.mapSlicesFunc
 	strats[arrays] = m.mapArraysFunc
 	strats[sameTypes] = m.mapSameTypesFunc
-	strats[sameTypes] = m.mapSameTypesFunc
 	strats[converterFunc] = m.mapConverterFunc
+	strats[fromRecords] = m.mapFromRecordFunc
+	strats[toRecords] = m.mapToRecordFunc
+	strats[selfMapping] = m.mapSelfFunc
+	strats[maps] = m.mapMapsFunc
+	strats[pointers] = m.mapPointersFunc
+	strats[interfaces] = m.mapInterfacesFunc
+	strats[unixSeconds] = m.mapUnixFunc(time.Second)
+	strats[unixMillis] = m.mapUnixFunc(time.Millisecond)
+	strats[jsonNumbers] = m.mapJSONNumberFunc
+	strats[unions] = m.mapUnionsFunc
+	strats[clones] = m.mapClonesFunc
+	strats[atomics] = m.mapAtomicsFunc
 	return strats
 }
 
-func isStructOrPtrToStruct(tp reflect.Type) bool {
-	return tp.Kind() == reflect.Struct || (tp.Kind() == reflect.Ptr && tp.Elem().Kind() == reflect.Struct)
-}
-
-func (m *Mapper) detectMappingType(fromVal, toVal fieldInfo) supportedType {
-	fromType := fromVal.val.Type()
-	toType := toVal.val.Type()
-	if _, ok := m.converters[converterInfo{from: fromVal.val.Type(), to: toVal.val.Type()}]; ok {
-		return converterFunc
+func (m *Mapper) initMatchers() map[supportedType]matchFunc {
+	matchers := make(map[supportedType]matchFunc)
+	matchers[converterFunc] = func(fromType, toType reflect.Type) bool {
+		_, _, ok := m.converterTable().lookup(fromType, toType)
+		return ok
 	}
-
-	if toType == fromType {
-		return sameTypes
+	matchers[selfMapping] = isSelfMapping
+	matchers[unions] = isUnionMapping
+	matchers[clones] = m.isCloneMapping
+	matchers[atomics] = isAtomicMapping
+	matchers[sameTypes] = func(fromType, toType reflect.Type) bool {
+		return toType == fromType
 	}
-
-	if isStructOrPtrToStruct(fromType) && isStructOrPtrToStruct(toType) {
-		return structs
+	matchers[structs] = func(fromType, toType reflect.Type) bool {
+		return isStructOrPtrToStruct(fromType) && isStructOrPtrToStruct(toType)
+	}
+	// elements of collections may be collections themselves, e.g. [][]*T
+	matchers[slices] = func(fromType, toType reflect.Type) bool {
+		return fromType.Kind() == reflect.Slice && toType.Kind() == reflect.Slice &&
+			m.detectMappingType(fromType.Elem(), toType.Elem()) != unsupported
+	}
+	matchers[arrays] = func(fromType, toType reflect.Type) bool {
+		return fromType.Kind() == reflect.Array && toType.Kind() == reflect.Array && fromType.Len() == toType.Len() &&
+			m.detectMappingType(fromType.Elem(), toType.Elem()) != unsupported
 	}
+	matchers[fromRecords] = func(fromType, toType reflect.Type) bool {
+		return isRecord(fromType) && isStructOrPtrToStruct(toType) && hasRecordFields(toType)
+	}
+	matchers[toRecords] = func(fromType, toType reflect.Type) bool {
+		return isStructOrPtrToStruct(fromType) && hasRecordFields(fromType) && isRecord(toType)
+	}
+	matchers[maps] = func(fromType, toType reflect.Type) bool {
+		return fromType.Kind() == reflect.Map && toType.Kind() == reflect.Map &&
+			m.detectMappingType(fromType.Key(), toType.Key()) != unsupported &&
+			m.detectMappingType(fromType.Elem(), toType.Elem()) != unsupported
+	}
+	matchers[interfaces] = func(fromType, toType reflect.Type) bool {
+		return toType.Kind() == reflect.Interface && (m.hasImplementations(toType) || m.hasVariants(toType)) &&
+			(fromType.Kind() == reflect.Interface || isStructOrPtrToStruct(fromType))
+	}
+	// epoch strategies are selected with field tag options only, see epochStrategy
+	matchers[unixSeconds] = func(reflect.Type, reflect.Type) bool { return false }
+	matchers[unixMillis] = matchers[unixSeconds]
+	matchers[jsonNumbers] = func(fromType, toType reflect.Type) bool {
+		return fromType == jsonNumberType && isJSONNumberTarget(toType)
+	}
+	matchers[pointers] = func(fromType, toType reflect.Type) bool {
+		if fromType.Kind() != reflect.Ptr && toType.Kind() != reflect.Ptr {
+			return false
+		}
+
+		// source is dereferenced fully, destination is allocated level by level
+		for fromType.Kind() == reflect.Ptr {
+			fromType = fromType.Elem()
+		}
+
+		if toType.Kind() == reflect.Ptr {
+			toType = toType.Elem()
+		}
 
-	if (fromType.Kind() == reflect.Slice && isStructOrPtrToStruct(fromType.Elem())) &&
-		(toType.Kind() == reflect.Slice && isStructOrPtrToStruct(toType.Elem())) {
-		return slices
+		return m.detectMappingType(fromType, toType) != unsupported
 	}
+	return matchers
+}
+
+func isStructOrPtrToStruct(tp reflect.Type) bool {
+	return tp.Kind() == reflect.Struct || (tp.Kind() == reflect.Ptr && tp.Elem().Kind() == reflect.Struct)
+}
 
-	if (fromType.Kind() == reflect.Array && isStructOrPtrToStruct(fromType.Elem())) &&
-		(toType.Kind() == reflect.Array && isStructOrPtrToStruct(toType.Elem())) {
-		return arrays
+func (m *Mapper) detectMappingType(fromType, toType reflect.Type) supportedType {
+	for _, strategy := range m.order {
+		if m.matchers[strategy](fromType, toType) {
+			return strategy
+		}
 	}
 
 	return unsupported
 }
 
-func (m *Mapper) mapStructsFunc(fromVal, toVal reflect.Value) error {
+func (m *Mapper) mapStructsFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	// pointer to pointer mapping is tracked, so that recursion over cycles terminates
+	if fromVal.Kind() == reflect.Ptr && toVal.Kind() == reflect.Ptr && !fromVal.IsNil() {
+		key := visitKey{from: fromVal.Pointer(), to: toVal.Type()}
+		if visited, ok := st.visited[key]; ok {
+			toVal.Set(visited)
+			return nil
+		}
+
+		ptr := m.allocate(st, toVal.Type().Elem())
+		toVal.Set(ptr)
+		st.visited[key] = ptr
+		return m.mapStructs(st, fromVal.Elem(), ptr.Elem())
+	}
+
 	// if from val is ptr - take Elem
 	if fromVal.Kind() == reflect.Ptr {
 		fromVal = fromVal.Elem()
@@ -72,10 +224,10 @@ func (m *Mapper) mapStructsFunc(fromVal, toVal reflect.Value) error {
 	// if to val is ptr - set ptr to zero value
 	// and pass Elem to mapper
 	if toVal.Kind() == reflect.Ptr {
-		toVal.Set(reflect.New(toVal.Type().Elem()))
-		err = m.mapStructs(fromVal, toVal.Elem())
+		toVal.Set(m.allocate(st, toVal.Type().Elem()))
+		err = m.mapStructs(st, fromVal, toVal.Elem())
 	} else {
-		err = m.mapStructs(fromVal, toVal)
+		err = m.mapStructs(st, fromVal, toVal)
 	}
 
 	if err != nil {
@@ -85,9 +237,10 @@ func (m *Mapper) mapStructsFunc(fromVal, toVal reflect.Value) error {
 	return nil
 }
 
-func (m *Mapper) mapSlicesFunc(fromVal, toVal reflect.Value) error {
+func (m *Mapper) mapSlicesFunc(st *mapState, fromVal, toVal reflect.Value) error {
 	slice := reflect.MakeSlice(toVal.Type(), fromVal.Len(), fromVal.Len())
-	err := m.setArrayValue(fromVal, toVal, slice)
+	m.initElems(slice)
+	err := m.setArrayValue(st, fromVal, slice)
 	if err != nil {
 		return fmt.Errorf("error in setArrayValue: %w", err)
 	}
@@ -96,9 +249,10 @@ func (m *Mapper) mapSlicesFunc(fromVal, toVal reflect.Value) error {
 	return nil
 }
 
-func (m *Mapper) mapArraysFunc(fromVal, toVal reflect.Value) error {
-	array := reflect.New(reflect.ArrayOf(fromVal.Len(), toVal.Type().Elem())).Elem()
-	err := m.setArrayValue(fromVal, toVal, array)
+func (m *Mapper) mapArraysFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	array := reflect.New(toVal.Type()).Elem()
+	m.initElems(array)
+	err := m.setArrayValue(st, fromVal, array)
 	if err != nil {
 		return fmt.Errorf("error in setArrayValue: %w", err)
 	}
@@ -107,72 +261,325 @@ func (m *Mapper) mapArraysFunc(fromVal, toVal reflect.Value) error {
 	return nil
 }
 
-func (m *Mapper) setArrayValue(fromVal, toVal, array reflect.Value) error {
-	for i := 0; i < fromVal.Len(); i++ {
-		var arrayElem reflect.Value
-		// if target array's element kind is pointer - take Elem of it to get struct type
-		toElemType := toVal.Type().Elem()
-		if toElemType.Kind() == reflect.Ptr {
-			arrayElem = reflect.New(toElemType.Elem())
-		} else {
-			// already a struct type
-			arrayElem = reflect.New(toElemType)
-		}
+// setArrayValue maps fromVal elements into array elements one by one,
+// zero elements are left zero like zero fields are.
+func (m *Mapper) setArrayValue(st *mapState, fromVal, array reflect.Value) error {
+	return m.setArrayRange(st, fromVal, array, 0, fromVal.Len())
+}
 
-		fromElemType := fromVal.Type().Elem()
-		var err error
-		// if from array's element kind is struct - take it
-		if fromElemType.Kind() == reflect.Struct {
-			// take Elem of arrayElem because it's a pointer
-			err = m.mapStructs(fromVal.Index(i), arrayElem.Elem())
+// setArrayRange maps fromVal elements with indexes from start to end into array elements.
+func (m *Mapper) setArrayRange(st *mapState, fromVal, array reflect.Value, start, end int) error {
+	for i := start; i < end; i++ {
+		if err := st.checkContext(i); err != nil {
+			return err
 		}
-		// if from array's element kind is pointer - take Elem of it to get struct
-		if fromElemType.Kind() == reflect.Ptr {
-			// take Elem of arrayElem because it's a pointer
-			err = m.mapStructs(fromVal.Index(i).Elem(), arrayElem.Elem())
+
+		if fromVal.Index(i).IsZero() {
+			if err := checkNilSource(st, fromVal.Index(i), array.Index(i)); err != nil {
+				return withFieldPath(err, "["+strconv.Itoa(i)+"]")
+			}
+
+			continue
 		}
 
-		if err != nil {
-			return err
+		if m.tracksPath(st) {
+			m.enterIndex(st, i)
 		}
 
-		// if target array's element type is pointer - append pointer value
-		if toElemType.Kind() == reflect.Ptr {
-			array.Index(i).Set(arrayElem)
-		} else {
-			// append struct value
-			array.Index(i).Set(arrayElem.Elem())
+		err := m.mapValue(st, fromVal.Index(i), array.Index(i))
+		m.leavePath(st)
+		if err != nil {
+			return withFieldPath(err, "["+strconv.Itoa(i)+"]")
 		}
 	}
 
 	return nil
 }
 
-func (m *Mapper) mapSameTypesFunc(fromVal, toVal reflect.Value) error {
+func (m *Mapper) mapSameTypesFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if st.fieldwise(fromVal.Type()) {
+		return m.mapSelected(st, fromVal, toVal)
+	}
+
+	if m.holdsPooled(fromVal.Type()) {
+		return m.mapPooled(st, fromVal, toVal)
+	}
+
+	if st.opts.copyInterfaces && fromVal.Kind() == reflect.Interface {
+		return m.copyInterface(st, fromVal, toVal)
+	}
+
+	if st.opts.normalizesTime() && containsTime(fromVal.Type()) {
+		return m.mapTimes(st, fromVal, toVal)
+	}
+
+	if st.opts.specialFloats != specialFloatsPass && hasFloatElems(fromVal.Type()) {
+		return m.mapFloatElems(st, fromVal, toVal)
+	}
+
 	toVal.Set(fromVal)
 	return nil
 }
 
-func (m *Mapper) mapConverterFunc(fromVal, toVal reflect.Value) error {
-	converter, ok := m.converters[converterInfo{from: fromVal.Type(), to: toVal.Type()}]
+func (m *Mapper) mapConverterFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	converter, shape, ok := m.converterTable().lookup(fromVal.Type(), toVal.Type())
 	if !ok {
 		return ErrMissingConverter
 	}
 
+	return m.callShaped(converter, shape, fromVal, toVal)
+}
+
+func (m *Mapper) callConverter(converter, fromVal, toVal reflect.Value) error {
+	if !m.frozen {
+		m.stats.converterCalls.Add(1)
+	}
+
 	outArgs := converter.Call([]reflect.Value{fromVal})
 	toVal.Set(outArgs[0])
 	if len(outArgs) == 1 {
 		return nil
 	}
 
-	err, ok := outArgs[1].Interface().(error)
-	if !ok {
+	if !outArgs[1].Type().Implements(errorType) {
 		return ErrConverterErrorUnknownType
 	}
 
+	// nil error can't be asserted from interface value
+	if isNilError(outArgs[1]) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", ErrConverter, outArgs[1].Interface().(error))
+}
+
+// mapValue maps from into to with the strategy detected for their types.
+func (m *Mapper) mapValue(st *mapState, fromVal, toVal reflect.Value) error {
+	mapperFunc := m.scopedMapper(st, fromVal.Type(), toVal.Type())
+	if mapperFunc == nil {
+		mapperFunc = m.detectedMapper(st, fromVal.Type(), toVal.Type())
+	}
+
+	if mapperFunc == nil {
+		if converted, err := m.mapConverted(st, fromVal, toVal); converted {
+			return err
+		}
+	}
+
+	if mapperFunc == nil && st.opts.weak {
+		return m.mapWeak(st, fromVal, toVal)
+	}
+
+	if mapperFunc == nil {
+		return m.missingConverter(fromVal, toVal)
+	}
+
+	err := mapperFunc(st, fromVal, toVal)
+	if err == nil && st.opts.specialFloats != specialFloatsPass {
+		err = checkSpecialFloat(st, toVal)
+	}
+
+	return err
+}
+
+// mapConverted maps from into to with conversions of basic values enabled for the call, e.g. by
+// WithNumericConversion, WithStringNumberConversions or WithBoolStrings, so that they apply to fields
+// as well as to elements of collections and values of pointers. It reports whether any conversion applies.
+func (m *Mapper) mapConverted(st *mapState, from, to reflect.Value) (bool, error) {
+	if st.opts.boolStrings == nil && !st.opts.stringNumbers && !st.opts.numeric {
+		return false, nil
+	}
+
+	switch {
+	case st.opts.boolStrings != nil && isBoolString(from.Kind(), to.Kind()):
+		return true, st.opts.boolStrings.convert(from, to)
+	case st.opts.stringNumbers && isStringNumber(from.Kind(), to.Kind()):
+		return true, convertStringNumber(from, to)
+	case st.opts.numeric && isNumeric(from.Kind()) && isNumeric(to.Kind()):
+		return true, convertNumeric(st, from, to)
+	case from.Kind() == reflect.Ptr && to.Kind() != reflect.Ptr:
+		// nil pointers leave destination unchanged
+		if from.IsNil() {
+			return m.convertible(st, from.Type().Elem(), to.Type()), nil
+		}
+
+		return m.mapConverted(st, from.Elem(), to)
+	case to.Kind() == reflect.Ptr && from.Kind() != reflect.Ptr && m.convertible(st, from.Type(), to.Type().Elem()):
+		elem := m.allocate(st, to.Type().Elem())
+		if _, err := m.mapConverted(st, from, elem.Elem()); err != nil {
+			return true, err
+		}
+
+		to.Set(elem)
+		return true, nil
+	case isList(from.Kind()) && isList(to.Kind()) && m.convertible(st, from.Type(), to.Type()):
+		return true, m.mapConvertedElems(st, from, to)
+	case from.Kind() == reflect.Map && to.Kind() == reflect.Map && m.convertible(st, from.Type(), to.Type()):
+		return true, m.mapConvertedEntries(st, from, to)
+	default:
+		return false, nil
+	}
+}
+
+// convertible reports whether mapConverted converts values of from type into to type,
+// or elements of such collections.
+func (m *Mapper) convertible(st *mapState, from, to reflect.Type) bool {
+	for from.Kind() == reflect.Ptr {
+		from = from.Elem()
+	}
+
+	switch {
+	case isList(from.Kind()) && isList(to.Kind()):
+		return m.convertible(st, from.Elem(), to.Elem())
+	case from.Kind() == reflect.Map && to.Kind() == reflect.Map:
+		return from.Key() == to.Key() && m.convertible(st, from.Elem(), to.Elem())
+	}
+
+	return (st.opts.boolStrings != nil && isBoolString(from.Kind(), to.Kind())) ||
+		(st.opts.stringNumbers && isStringNumber(from.Kind(), to.Kind())) ||
+		(st.opts.numeric && isNumeric(from.Kind()) && isNumeric(to.Kind()))
+}
+
+func isList(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// mapConvertedElems maps elements of slice or array from into elements of slice or array to.
+func (m *Mapper) mapConvertedElems(st *mapState, from, to reflect.Value) error {
+	if from.Kind() == reflect.Slice && from.IsNil() {
+		return nil
+	}
+
+	elems := to
+	if to.Kind() == reflect.Slice {
+		elems = reflect.MakeSlice(to.Type(), from.Len(), from.Len())
+	}
+
+	for i := 0; i < from.Len() && i < elems.Len(); i++ {
+		if err := m.mapValue(st, from.Index(i), elems.Index(i)); err != nil {
+			return withFieldPath(err, "["+strconv.Itoa(i)+"]")
+		}
+	}
+
+	to.Set(elems)
+	return nil
+}
+
+// mapConvertedEntries maps values of map from into new map to.
+func (m *Mapper) mapConvertedEntries(st *mapState, from, to reflect.Value) error {
+	if from.IsNil() {
+		return nil
+	}
+
+	entries := reflect.MakeMapWithSize(to.Type(), from.Len())
+	iter := from.MapRange()
+	for iter.Next() {
+		value := reflect.New(to.Type().Elem()).Elem()
+		if err := m.mapValue(st, iter.Value(), value); err != nil {
+			return withFieldPath(err, fmt.Sprintf("[%v]", iter.Key()))
+		}
+
+		entries.SetMapIndex(iter.Key(), value)
+	}
+
+	to.Set(entries)
+	return nil
+}
+
+// detectedMapper returns mapper func of strategy detected for types, or of collection strategy
+// lifting converters of the call, nil if types can't be mapped.
+func (m *Mapper) detectedMapper(st *mapState, from, to reflect.Type) mapperFunc {
+	if mappingType := m.detectMappingType(from, to); mappingType != unsupported {
+		return m.strats[mappingType]
+	}
+
+	return m.liftedScoped(st, from, to)
+}
+
+func (m *Mapper) mapMapsFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	toType := toVal.Type()
+	mapVal := reflect.MakeMapWithSize(toType, fromVal.Len())
+	iter := fromVal.MapRange()
+	var failed entryError
+	for i := 0; iter.Next(); i++ {
+		if err := st.checkContext(i); err != nil {
+			return err
+		}
+
+		key, elem, err := m.mapEntry(st, iter.Key(), iter.Value(), toType)
+		if err != nil {
+			failed.record(iter.Key(), err)
+			continue
+		}
+
+		mapVal.SetMapIndex(key, elem)
+	}
+
+	if failed.err != nil {
+		return failed.err
+	}
+
+	toVal.Set(mapVal)
+	return nil
+}
+
+// entryError is an error of failing map entry with the least key, so that reported error
+// doesn't depend on map iteration order. Entries are mapped once in iteration order,
+// since sorting keys of every map is costly.
+type entryError struct {
+	key reflect.Value
+	err error
+}
+
+func (e *entryError) record(key reflect.Value, err error) {
+	if e.err == nil || lessKey(key, e.key) {
+		e.key, e.err = key, err
+	}
+}
+
+// mapEntry maps map entry into key and element of map type toType.
+func (m *Mapper) mapEntry(st *mapState, fromKey, fromElem reflect.Value, toType reflect.Type) (reflect.Value, reflect.Value, error) {
+	key := reflect.New(toType.Key()).Elem()
+	err := m.mapValue(st, fromKey, key)
+	if err != nil {
+		return key, reflect.Value{}, withFieldPath(err, fmt.Sprintf("[%v]", fromKey))
+	}
+
+	elem := m.newValue(toType.Elem()).Elem()
+	if m.tracksPath(st) {
+		m.enterIndex(st, fromKey)
+	}
+
+	err = m.mapValue(st, fromElem, elem)
+	m.leavePath(st)
+	if err != nil {
+		return key, elem, withFieldPath(err, fmt.Sprintf("[%v]", fromKey))
+	}
+
+	return key, elem, nil
+}
+
+// mapPointersFunc dereferences source pointer chain and allocates destination pointer chain,
+// mapping their elements with strategy detected for them. Nil sources are skipped.
+func (m *Mapper) mapPointersFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	for fromVal.Kind() == reflect.Ptr {
+		if fromVal.IsNil() {
+			return nil
+		}
+
+		fromVal = fromVal.Elem()
+	}
+
+	if toVal.Kind() != reflect.Ptr {
+		return m.mapValue(st, fromVal, toVal)
+	}
+
+	elem := m.allocate(st, toVal.Type().Elem())
+	err := m.mapValue(st, fromVal, elem.Elem())
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrConverter, err)
+		return err
 	}
 
+	toVal.Set(elem)
 	return nil
 }
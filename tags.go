@@ -0,0 +1,152 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Tag options are comma-separated modifiers following field name in `mapper:"name,opt1,opt2=value"` tag,
+// name may be omitted, e.g. `mapper:",omitempty"`. Options are either flags or take a value.
+const (
+	tagOmitEmpty = "omitempty"
+	tagUnix      = "unix"
+	tagUnixMs    = "unixms"
+	tagCSV       = "csv"
+	// tagConverter selects converter registered with SetNamed.
+	tagConverter = "converter"
+	// tagSkipZero keeps non-zero destination field when mapped value is zero or nil, e.g. CreatedAt,
+	// even if zero values are mapped, e.g. with NilZero policy, explicit nulls or converters.
+	tagSkipZero = "skipzero"
+	// tagRequire fails mapping of destination field with *MissingSourceError if its source field
+	// is missing or zero, so that omissions of upstream data aren't mapped into zero values.
+	// Fields of values copied as a whole into fields of identical types aren't checked.
+	tagRequire = "require"
+	// tagRequired is an alias of tagRequire.
+	tagRequired = "required"
+	// tagIgnore excludes field from mapping between fields of struct pairs, source fields tagged with it
+	// are dropped intentionally and aren't reported as unused, see ReportUnusedSources.
+	tagIgnore = "ignore"
+	// tagSquash flattens fields of embedded struct into fields of the struct, like Flatten
+	// does for all embeddings of a type, e.g. `mapper:",squash"`.
+	tagSquash = "squash"
+	// tagTimeFormat maps time.Time field into string field and back with layout, e.g. `mapper:",timefmt=2006-01-02"`.
+	// Layouts can't contain commas, which separate tag options.
+	tagTimeFormat = "timefmt"
+)
+
+// stringTransforms are tag options of string destination fields transforming mapped values,
+// e.g. `mapper:"Email,trim,lower"`, applied in order of declaration.
+var stringTransforms = map[string]func(string) string{
+	"trim":  strings.TrimSpace,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// knownTagOptions reports whether known tag option takes a value.
+// Fields with unknown options fail mapping instead of ignoring typos.
+var knownTagOptions = map[string]bool{
+	tagOmitEmpty:  false,
+	remainOption:  false,
+	tagUnix:       false,
+	tagUnixMs:     false,
+	tagCSV:        true,
+	tagConverter:  true,
+	tagSkipZero:   false,
+	tagRequire:    false,
+	tagIgnore:     false,
+	tagRequired:   false,
+	tagSquash:     false,
+	tagTimeFormat: true,
+	"trim":        false,
+	"lower":       false,
+	"upper":       false,
+}
+
+// tagOption is a parsed tag option, value is empty for flags.
+type tagOption struct {
+	name, value string
+}
+
+// tagOptions are parsed options of field tag in order of declaration.
+type tagOptions []tagOption
+
+// parseTagOptions parses comma-separated tag options of field, validating them.
+func parseTagOptions(field string, parts []string) (tagOptions, error) {
+	opts := make(tagOptions, 0, len(parts))
+	for _, part := range parts {
+		name, value, hasValue := strings.Cut(part, "=")
+		takesValue, ok := knownTagOptions[name]
+		switch {
+		case !ok:
+			return opts, fmt.Errorf("%w: unknown option '%s' of field %s", ErrInvalidTag, part, field)
+		case takesValue && value == "":
+			return opts, fmt.Errorf("%w: option '%s' of field %s requires value", ErrInvalidTag, name, field)
+		case !takesValue && hasValue:
+			return opts, fmt.Errorf("%w: option '%s' of field %s takes no value", ErrInvalidTag, name, field)
+		}
+
+		if name == tagRequired {
+			name = tagRequire
+		}
+
+		opts = append(opts, tagOption{name: name, value: value})
+	}
+
+	return opts, nil
+}
+
+// has reports whether option with name is set.
+func (o tagOptions) has(name string) bool {
+	_, ok := o.value(name)
+	return ok
+}
+
+// value returns value of option with name, the first one if it's set more than once.
+func (o tagOptions) value(name string) (string, bool) {
+	for _, opt := range o {
+		if opt.name == name {
+			return opt.value, true
+		}
+	}
+
+	return "", false
+}
+
+// fieldwise reports whether options check or transform mapped values, see structFields.
+func (o tagOptions) fieldwise() bool {
+	for _, opt := range o {
+		if _, ok := stringTransforms[opt.name]; ok || opt.name == tagRequire || opt.name == tagConverter || opt.name == tagIgnore {
+			return true
+		}
+	}
+
+	return false
+}
+
+// transform returns func applying string transformations of options in order,
+// or error if they are set on field of destination type, which isn't a string.
+func (o tagOptions) transform(field string, toType reflect.Type) (func(string) string, error) {
+	var transforms []func(string) string
+	for _, opt := range o {
+		if fn, ok := stringTransforms[opt.name]; ok {
+			if toType.Kind() != reflect.String {
+				return nil, fmt.Errorf("%w: option '%s' of field %s requires string field", ErrInvalidTag, opt.name, field)
+			}
+
+			transforms = append(transforms, fn)
+		}
+	}
+
+	if len(transforms) == 0 {
+		return nil, nil
+	}
+
+	return func(s string) string {
+		for _, fn := range transforms {
+			s = fn(s)
+		}
+
+		return s
+	}, nil
+}
@@ -0,0 +1,208 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// WithTimeLocation converts every mapped time.Time value to loc, including values
+// of nested structs, e.g. WithTimeLocation(time.UTC).
+func WithTimeLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.timeLocation = loc
+	}
+}
+
+// WithoutMonotonic strips monotonic clock reading from every mapped time.Time value
+// with Round(0), so that mapped values are comparable with reflect.DeepEqual
+// to values read back from databases or encoded values.
+func WithoutMonotonic() Option {
+	return func(o *options) {
+		o.stripMonotonic = true
+	}
+}
+
+// normalizesTime reports whether time.Time values are changed while mapping.
+func (o *options) normalizesTime() bool {
+	return o.timeLocation != nil || o.stripMonotonic
+}
+
+func (o *options) normalizeTime(t time.Time) time.Time {
+	if o.timeLocation != nil {
+		t = t.In(o.timeLocation)
+	}
+
+	if o.stripMonotonic {
+		t = t.Round(0)
+	}
+
+	return t
+}
+
+func (o *options) normalizeTimeValue(val reflect.Value) reflect.Value {
+	t, ok := val.Interface().(time.Time)
+	if !ok {
+		return val
+	}
+
+	return reflect.ValueOf(o.normalizeTime(t))
+}
+
+// mapTimes maps values of identical types, which contain time.Time, normalizing times.
+func (m *Mapper) mapTimes(st *mapState, fromVal, toVal reflect.Value) error {
+	switch {
+	case fromVal.Type() == timeType:
+		toVal.Set(st.opts.normalizeTimeValue(fromVal))
+		return nil
+	case fromVal.Kind() == reflect.Ptr && fromVal.Type().Elem() == timeType:
+		normalized := reflect.New(timeType)
+		normalized.Elem().Set(st.opts.normalizeTimeValue(fromVal.Elem()))
+		toVal.Set(normalized)
+		return nil
+	case fromVal.Kind() == reflect.Slice:
+		// elements are mapped one by one, so that times shared with source aren't changed
+		return m.mapSlicesFunc(st, fromVal, toVal)
+	case fromVal.Kind() == reflect.Array:
+		return m.mapArraysFunc(st, fromVal, toVal)
+	case fromVal.Kind() == reflect.Map:
+		return m.mapMapsFunc(st, fromVal, toVal)
+	default:
+		// structs with time fields are mapped field by field
+		return m.mapStructsFunc(st, fromVal, toVal)
+	}
+}
+
+// epochStrategy returns epoch strategy selected by `mapper:",unix"` (seconds)
+// or `mapper:",unixms"` (milliseconds) tag option of source or destination field
+// for mapping between time.Time and int or int64.
+func epochStrategy(opts tagOptions, fromType, toType reflect.Type) (supportedType, bool) {
+	if !(fromType == timeType && isEpochKind(toType.Kind())) && !(toType == timeType && isEpochKind(fromType.Kind())) {
+		return unsupported, false
+	}
+
+	for _, opt := range opts {
+		switch opt.name {
+		case tagUnix:
+			return unixSeconds, true
+		case tagUnixMs:
+			return unixMillis, true
+		}
+	}
+
+	return unsupported, false
+}
+
+func isEpochKind(kind reflect.Kind) bool {
+	return kind == reflect.Int || kind == reflect.Int64
+}
+
+// mapUnixFunc returns strategy mapping time.Time into seconds or milliseconds since Unix epoch and back.
+// Times are created in local location, unless WithTimeLocation is used.
+func (m *Mapper) mapUnixFunc(unit time.Duration) mapperFunc {
+	return func(st *mapState, fromVal, toVal reflect.Value) error {
+		if t, ok := fromVal.Interface().(time.Time); ok {
+			if unit == time.Millisecond {
+				toVal.SetInt(t.UnixMilli())
+			} else {
+				toVal.SetInt(t.Unix())
+			}
+
+			return nil
+		}
+
+		t := time.Unix(fromVal.Int(), 0)
+		if unit == time.Millisecond {
+			t = time.UnixMilli(fromVal.Int())
+		}
+
+		toVal.Set(reflect.ValueOf(st.opts.normalizeTime(t)))
+		return nil
+	}
+}
+
+// bindTimeFormat binds formatting time.Time into string with layout of `mapper:",timefmt=layout"`
+// tag option and parsing it back, reporting whether op maps between time.Time and string.
+// Parsed times are UTC, unless layout has zone or WithTimeLocation is used.
+func bindTimeFormat(op *fieldOp, layout string) bool {
+	if !(op.fromType == timeType && op.toType.Kind() == reflect.String) &&
+		!(op.toType == timeType && op.fromType.Kind() == reflect.String) {
+		return false
+	}
+
+	// converter isn't set, so that exported plans with formats are rebuilt
+	op.strategy = converterFunc
+	op.mapperFunc = func(st *mapState, fromVal, toVal reflect.Value) error {
+		if t, ok := fromVal.Interface().(time.Time); ok {
+			toVal.SetString(t.Format(layout))
+			return nil
+		}
+
+		t, err := time.Parse(layout, fromVal.String())
+		if err != nil {
+			return fmt.Errorf("error in time.Parse: %w", err)
+		}
+
+		toVal.Set(reflect.ValueOf(st.opts.normalizeTime(t)))
+		return nil
+	}
+
+	return true
+}
+
+// timeTypesCache holds containsTime results by reflect.Type.
+var timeTypesCache sync.Map
+
+// containsTime reports whether tp is time.Time, pointer to it, a struct (or pointer to struct)
+// which fields contain time.Time, or a slice, array or map which elements do. Structs with
+// unexported fields are not considered, they are copied as a whole to keep unexported values.
+func containsTime(tp reflect.Type) bool {
+	if cached, ok := timeTypesCache.Load(tp); ok {
+		if contains, ok := cached.(bool); ok {
+			return contains
+		}
+	}
+
+	contains := checkContainsTime(tp, make(map[reflect.Type]bool))
+	timeTypesCache.Store(tp, contains)
+	return contains
+}
+
+// checkContainsTime implements containsTime, visiting guards against recursive types.
+func checkContainsTime(tp reflect.Type, visiting map[reflect.Type]bool) bool {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp == timeType {
+		return true
+	}
+
+	switch tp.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return checkContainsTime(tp.Elem(), visiting)
+	}
+
+	if tp.Kind() != reflect.Struct || visiting[tp] {
+		return false
+	}
+
+	visiting[tp] = true
+	fields := cachedFields(tp)
+	for _, meta := range fields.list {
+		if !meta.exported {
+			return false
+		}
+	}
+
+	for _, meta := range fields.list {
+		if checkContainsTime(tp.Field(meta.index).Type, visiting) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package automapper
+
+import "reflect"
+
+// WithSkipEqualWrites makes fields, which mapped values are equal to destination values, left untouched,
+// so that change-tracking destinations, e.g. ORM entities or UI bindings, aren't marked modified
+// by no-op mappings. Values are compared with reflect.DeepEqual, struct fields are compared field by field.
+func WithSkipEqualWrites() Option {
+	return func(o *options) {
+		o.skipEqual = true
+	}
+}
+
+// MapTrack maps two structs like Map, returning paths of destination fields written with values
+// different from their previous values, e.g. "Name" or "Address.City", so that audit logs and UPDATE
+// statements contain touched fields only. Values are compared like WithSkipEqualWrites compares them.
+// Nested structs are tracked field by field, other values, e.g. slices or time.Time, are tracked as a whole.
+// Paths of fields of new structs, e.g. allocated pointers, follow paths of fields holding them.
+func (m *Mapper) MapTrack(from, to interface{}, opts ...Option) ([]string, error) {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	changes := []string{}
+	st.changes = &changes
+	err := m.mapValues(st, from, to)
+	if err != nil {
+		return nil, m.translateError(err)
+	}
+
+	return changes, nil
+}
+
+// mapChanged maps field of op into copy of to, which is set into to when it differs from to,
+// or always unless equal writes are skipped. Changes of nested fields are tracked only if to changes.
+func (m *Mapper) mapChanged(st *mapState, op *fieldOp, from, to reflect.Value) error {
+	tracked := 0
+	if st.changes != nil {
+		tracked = len(*st.changes)
+	}
+
+	mapped := reflect.New(to.Type()).Elem()
+	mapped.Set(to)
+	err := m.mapField(st, op, from, mapped)
+	if err != nil {
+		return err
+	}
+
+	if st.opts.specialFloats != specialFloatsPass {
+		if err = checkSpecialFloat(st, mapped); err != nil {
+			return err
+		}
+	}
+
+	changed := !reflect.DeepEqual(mapped.Interface(), to.Interface())
+	if changed || !st.opts.skipEqual {
+		to.Set(mapped)
+	}
+
+	if st.changes != nil {
+		trackChange(st, tracked, changed)
+	}
+
+	return nil
+}
+
+// mapsFields reports whether to of op is a struct mapped field by field.
+func mapsFields(st *mapState, op *fieldOp, to reflect.Value) bool {
+	return to.Kind() == reflect.Struct && (op.strategy == structs || (op.strategy == sameTypes && st.fieldwise(to.Type())))
+}
+
+// trackChange inserts path of changed field before changes of its nested fields tracked since
+// the tracked count, or drops them if the field doesn't change.
+func trackChange(st *mapState, tracked int, changed bool) {
+	changes := *st.changes
+	if !changed {
+		*st.changes = changes[:tracked]
+		return
+	}
+
+	changes = append(changes, "")
+	copy(changes[tracked+1:], changes[tracked:])
+	changes[tracked] = formatPath(st.path)
+	*st.changes = changes
+}
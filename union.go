@@ -0,0 +1,83 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Union is implemented by union wrappers, e.g. UnionNullString structs generated for Avro schemas,
+// so that they are mapped by their active member. Methods can be added to generated types
+// in a separate file of their package.
+type Union interface {
+	// UnionValue returns value of the active member, nil if union is null.
+	UnionValue() interface{}
+}
+
+// UnionSetter is implemented by pointers to union wrappers mapped from plain values.
+type UnionSetter interface {
+	// SetUnionValue activates member of value type and sets its value.
+	SetUnionValue(value interface{}) error
+}
+
+var (
+	unionType       = reflect.TypeOf((*Union)(nil)).Elem()
+	unionSetterType = reflect.TypeOf((*UnionSetter)(nil)).Elem()
+)
+
+// isUnion reports whether tp or pointer to it implements Union.
+func isUnion(tp reflect.Type) bool {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	return reflect.PointerTo(tp).Implements(unionType)
+}
+
+func isUnionMapping(fromType, toType reflect.Type) bool {
+	return isUnion(fromType) || (toType.Kind() != reflect.Ptr && reflect.PointerTo(toType).Implements(unionSetterType))
+}
+
+// mapUnionsFunc maps active member of union from, or sets from as active member of union to.
+// Null unions are skipped like nil pointers.
+func (m *Mapper) mapUnionsFunc(st *mapState, fromVal, toVal reflect.Value) error {
+	if !isUnion(fromVal.Type()) {
+		setter, ok := toVal.Addr().Interface().(UnionSetter)
+		if !ok {
+			return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.Type(), toVal.Type())
+		}
+
+		err := setter.SetUnionValue(fromVal.Interface())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrConverter, err)
+		}
+
+		return nil
+	}
+
+	if fromVal.Kind() == reflect.Ptr {
+		if fromVal.IsNil() {
+			return nil
+		}
+
+		fromVal = fromVal.Elem()
+	}
+
+	if !fromVal.CanAddr() {
+		// UnionValue may have pointer receiver
+		ptr := reflect.New(fromVal.Type())
+		ptr.Elem().Set(fromVal)
+		fromVal = ptr.Elem()
+	}
+
+	union, ok := fromVal.Addr().Interface().(Union)
+	if !ok {
+		return fmt.Errorf("%w '%s -> %s'", ErrMissingConverter, fromVal.Type(), toVal.Type())
+	}
+
+	value := union.UnionValue()
+	if value == nil {
+		return nil
+	}
+
+	return m.mapValue(st, reflect.ValueOf(value), toVal)
+}
@@ -0,0 +1,79 @@
+package automapper
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// WithUnsafeCopy enables copying of structs of distinct types with identical memory layout as a whole,
+// bypassing per-field reflection. Layouts are identical when both types have the same exported fields
+// of the same types at the same offsets, in the same order, and every field is mapped from the field
+// at the same index. Structs are copied the same way only when field by field mapping would give
+// the same result, e.g. without converters, selections or options which change field values.
+func WithUnsafeCopy() Option {
+	return func(o *options) {
+		o.unsafeCopy = true
+	}
+}
+
+// layoutsCache holds sameLayout results by layoutPair.
+var layoutsCache sync.Map
+
+type layoutPair struct {
+	from, to reflect.Type
+}
+
+// canUnsafeCopy reports whether from can be copied into to as memory, see WithUnsafeCopy.
+func canUnsafeCopy(st *mapState, from, to reflect.Value, plan []fieldOp) bool {
+	if !to.CanAddr() || from.Type() == to.Type() || len(plan) != to.NumField() ||
+		st.opts.openAPI || !copyAllowed(st, from, to) {
+		return false
+	}
+
+	for i := range plan {
+		op := &plan[i]
+		if op.strategy != sameTypes || op.fromIndex != i || op.toIndex != i || op.getter >= 0 ||
+			op.fromPath != nil || op.toPath != nil || op.err != nil || op.tagErr != nil || op.require || op.transform != nil ||
+			op.oneofs != nil || op.remain != nil || op.remainKey != "" {
+			return false
+		}
+	}
+
+	return sameLayout(from.Type(), to.Type())
+}
+
+// sameLayout reports whether struct types from and to have identical memory layout.
+func sameLayout(from, to reflect.Type) bool {
+	pair := layoutPair{from: from, to: to}
+	if cached, ok := layoutsCache.Load(pair); ok {
+		if same, ok := cached.(bool); ok {
+			return same
+		}
+	}
+
+	same := checkSameLayout(from, to)
+	layoutsCache.Store(pair, same)
+	return same
+}
+
+func checkSameLayout(from, to reflect.Type) bool {
+	if from.Size() != to.Size() || from.Align() != to.Align() || from.NumField() != to.NumField() {
+		return false
+	}
+
+	for i := 0; i < from.NumField(); i++ {
+		fromField, toField := from.Field(i), to.Field(i)
+		if !fromField.IsExported() || !toField.IsExported() ||
+			fromField.Type != toField.Type || fromField.Offset != toField.Offset {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unsafeCopy copies memory of from into addressable to of the same layout.
+func unsafeCopy(from, to reflect.Value) {
+	reflect.NewAt(from.Type(), unsafe.Pointer(to.UnsafeAddr())).Elem().Set(from)
+}
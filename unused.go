@@ -0,0 +1,99 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ValidationCheck enables optional diagnostics of AssertConfigurationIsValid.
+type ValidationCheck func(*validation)
+
+type validation struct {
+	unusedSources bool
+}
+
+// ReportUnusedSources reports exported source fields of pairs, which no destination field is mapped from,
+// with ErrUnusedSource, as they usually indicate data silently dropped after a refactor.
+// Source fields tagged with `mapper:",ignore"` are dropped intentionally and aren't reported.
+func ReportUnusedSources() ValidationCheck {
+	return func(v *validation) {
+		v.unusedSources = true
+	}
+}
+
+// WithStrictSource fails mapping of structs of distinct types with ErrUnusedSource if any exported source field
+// isn't mapped into a destination field, for pairs which must be transferred without losses,
+// e.g. translated events. Source fields tagged with `mapper:",ignore"` may be dropped.
+func WithStrictSource() Option {
+	return func(o *options) {
+		o.strictSource = true
+	}
+}
+
+// checkStrictSource returns error listing source fields of from, which plan doesn't map into to.
+func checkStrictSource(st *mapState, from, to reflect.Type, plan []fieldOp) error {
+	pair := structMappingInfo{from: from, to: to, protobuf: st.opts.protobuf, graphql: st.opts.graphql}
+	names := unusedSources(pair, plan)
+	if len(names) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s of '%s' into '%s'", ErrUnusedSource, strings.Join(names, ", "), from, to)
+}
+
+// unusedSourceErrors returns errors of source fields of pair, which plan doesn't read.
+func unusedSourceErrors(pair structMappingInfo, plan []fieldOp) []error {
+	var errs []error
+	for _, name := range unusedSources(pair, plan) {
+		errs = append(errs, fmt.Errorf("%s.%s: %w into '%s'", pair.from, name, ErrUnusedSource, pair.to))
+	}
+
+	return errs
+}
+
+// unusedSources returns names of exported source fields of pair, which plan doesn't read.
+func unusedSources(pair structMappingInfo, plan []fieldOp) []string {
+	used := make(map[string]bool, len(plan))
+	for i := range plan {
+		markUsedSources(pair, &plan[i], used)
+	}
+
+	var names []string
+	for _, meta := range pair.fields(pair.from).list {
+		if !meta.exported || meta.hasOption(tagIgnore) || (pair.protobuf && isProtobufInternalField(meta.name)) {
+			continue
+		}
+
+		if field := pair.from.Field(meta.index); !used[field.Name] {
+			names = append(names, field.Name)
+		}
+	}
+
+	return names
+}
+
+// markUsedSources marks names of source fields of pair read by op as used.
+func markUsedSources(pair structMappingInfo, op *fieldOp, used map[string]bool) {
+	for i := range op.oneofs {
+		markUsedSources(pair, &op.oneofs[i].op, used)
+	}
+
+	for _, field := range op.remain {
+		used[pair.from.Field(field.index).Name] = true
+	}
+
+	switch {
+	case op.fromType == nil:
+	case len(op.fromPath) > 0:
+		used[pair.from.Field(op.fromPath[0]).Name] = true
+	case op.getter >= 0:
+		used[strings.TrimPrefix(reflect.PointerTo(pair.from).Method(op.getter).Name, "Get")] = true
+	default:
+		used[pair.from.Field(op.fromIndex).Name] = true
+	}
+
+	if op.money != nil {
+		used[pair.from.Field(op.money.currency).Name] = true
+	}
+}
@@ -0,0 +1,96 @@
+package automapper
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// MapURLValues maps form or query values into the struct pointed by to.
+// Keys match destination fields the same way MapMasked paths do. Values are parsed
+// with converters from string registered with Set, e.g. strconv.Atoi or strconv.ParseBool,
+// or parsed into basic kinds and time.Duration without them, like values of string maps mapped with Map.
+// Slice fields receive every value of the key, other fields receive the first one.
+// Errors are *Error like errors of Map.
+func (m *Mapper) MapURLValues(from url.Values, to interface{}, opts ...Option) error {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	return m.translateError(m.mapURLValues(st, from, to))
+}
+
+func (m *Mapper) mapURLValues(st *mapState, from url.Values, to interface{}) error {
+	if err := st.opts.err(); err != nil {
+		return err
+	}
+
+	valTo := reflect.ValueOf(to)
+	if valTo.Kind() != reflect.Ptr || !isStructOrPtrToStruct(valTo.Type()) {
+		return ErrNotAPtr
+	}
+
+	parseState := parsingState(st)
+	for _, key := range keysByFields(from, valTo.Elem().Type()) {
+		toVal, ok := destFieldByKey(st, valTo.Elem(), key)
+		if !ok {
+			continue
+		}
+
+		err := m.setStrings(parseState, from[key], toVal)
+		if err != nil {
+			meta, _ := cachedFields(valTo.Elem().Type()).fieldByKey(key)
+			return withFieldPath(err, meta.name)
+		}
+	}
+
+	return nil
+}
+
+// URLValues maps the struct pointed by from into url.Values, e.g. to build query strings.
+// Keys are field names or mapper tags. Values are formatted with converters to string
+// registered with Set, e.g. strconv.Itoa. Slice fields produce a value per element,
+// nil pointers are omitted, and so are zero values of fields tagged with `mapper:",omitempty"`.
+// Errors are *Error like errors of Map.
+func (m *Mapper) URLValues(from interface{}, opts ...Option) (url.Values, error) {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	values, err := m.urlValues(st, from)
+	return values, m.translateError(err)
+}
+
+func (m *Mapper) urlValues(st *mapState, from interface{}) (url.Values, error) {
+	if err := st.opts.err(); err != nil {
+		return nil, err
+	}
+
+	valFrom := reflect.ValueOf(from)
+	if valFrom.Kind() != reflect.Ptr || !isStructOrPtrToStruct(valFrom.Type()) {
+		return nil, ErrNotAPtr
+	}
+
+	valFrom = valFrom.Elem()
+	values := make(url.Values)
+	for _, meta := range cachedFields(valFrom.Type()).list {
+		fieldVal := valFrom.Field(meta.index)
+		if meta.err != nil {
+			return nil, meta.err
+		}
+
+		if !meta.exported || (fieldVal.IsZero() && meta.hasOption(tagOmitEmpty)) {
+			continue
+		}
+
+		if _, ok := st.field(meta.name); !ok {
+			continue
+		}
+
+		vals, err := m.formatStrings(st, fieldVal)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(vals) > 0 {
+			values[meta.name] = vals
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,80 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// variant is a pair of oneof wrapper and domain type registered with RegisterVariant.
+type variant struct {
+	wrapper, domain reflect.Type
+}
+
+// RegisterVariant registers mapping between protobuf oneof wrapper and domain variant
+// of a sum type, so that interface fields holding them are mapped by populated case:
+//
+//	m.RegisterVariant(&pb.Event_Created{}, CreatedEvent{})
+//	m.RegisterVariant(&pb.Event_Deleted{}, &DeletedEvent{})
+//
+// Wrapper field value is mapped into new domain value, and domain value is mapped
+// into wrapper field. Wrapper must be a pointer to struct with a single exported field.
+func (m *Mapper) RegisterVariant(wrapper, domain interface{}) error {
+	wrapperType, domainType := reflect.TypeOf(wrapper), reflect.TypeOf(domain)
+	if wrapperType == nil || !isOneofWrapper(wrapperType) {
+		return fmt.Errorf("%w '%v'", ErrNotAOneofWrapper, wrapperType)
+	}
+
+	if domainType == nil {
+		return ErrNilValue
+	}
+
+	m.variants = append(m.variants, variant{wrapper: wrapperType, domain: domainType})
+	m.resetPlans()
+	return nil
+}
+
+// hasVariants reports whether registered variant pair has a type implementing interface type tp.
+func (m *Mapper) hasVariants(tp reflect.Type) bool {
+	for _, v := range m.variants {
+		if v.wrapper.Implements(tp) || v.domain.Implements(tp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// variantOf returns registered variant pair of from type, which counterpart implements to,
+// and whether from is mapped into wrapper.
+func (m *Mapper) variantOf(from, to reflect.Type) (variant, bool, bool) {
+	for _, v := range m.variants {
+		switch {
+		case v.wrapper == from && v.domain.Implements(to):
+			return v, false, true
+		case v.domain == from && v.wrapper.Implements(to):
+			return v, true, true
+		}
+	}
+
+	return variant{}, false, false
+}
+
+// mapVariant maps oneof wrapper into domain variant or domain variant into wrapper.
+func (m *Mapper) mapVariant(st *mapState, fromVal, toVal reflect.Value, v variant, toWrapper bool) error {
+	var mapped reflect.Value
+	var err error
+	if toWrapper {
+		mapped = reflect.New(v.wrapper.Elem())
+		err = m.mapValue(st, fromVal, mapped.Elem().Field(0))
+	} else {
+		mapped = reflect.New(v.domain).Elem()
+		err = m.mapValue(st, fromVal.Elem().Field(0), mapped)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error in mapValue: %w", err)
+	}
+
+	toVal.Set(mapped)
+	return nil
+}
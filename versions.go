@@ -0,0 +1,110 @@
+package automapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapVersioned maps struct from into struct to through the shortest chain of pairs declared
+// with CreateMap, e.g. V1 into V3 through V2 with pairs V1 -> V2 and V2 -> V3 declared,
+// so that every migration between adjacent API versions is configured once.
+// Intermediate values are new or created by factories, see SetFactory, options apply to every step.
+// ErrMissingChain is returned if no chain of declared pairs leads from type of from into type of to.
+func (m *Mapper) MapVersioned(from, to interface{}, opts ...Option) error {
+	fromType, toType := structType(reflect.TypeOf(from)), structType(reflect.TypeOf(to))
+	if fromType == nil || toType == nil {
+		return m.translateError(fmt.Errorf("%w '%T -> %T'", ErrNotAStruct, from, to))
+	}
+
+	chain, ok := m.versionChain(fromType, toType)
+	if !ok {
+		return m.translateError(fmt.Errorf("%w '%s -> %s'", ErrMissingChain, fromType, toType))
+	}
+
+	return m.mapVia(from, to, chain[:len(chain)-1], opts)
+}
+
+// MapVia maps from into to through new value of intermediate type Mid, e.g. a canonical model:
+//
+//	err := automapper.MapVia[Order](m, &orderRow, &orderDTO)
+//
+// so that pairs of both sides are configured with Mid only instead of every pair being duplicated.
+// Intermediate value is new or created by factory, see SetFactory, options apply to both steps.
+func MapVia[Mid any](m *Mapper, from, to interface{}, opts ...Option) error {
+	return m.mapVia(from, to, []reflect.Type{reflect.TypeOf((*Mid)(nil)).Elem()}, opts)
+}
+
+// mapVia maps from into new values of intermediate types in turn, and the last of them into to.
+func (m *Mapper) mapVia(from, to interface{}, via []reflect.Type, opts []Option) error {
+	st := m.newMapState(opts)
+	defer releaseMapState(st)
+	current := from
+	for i := 0; i <= len(via); i++ {
+		stepTo := to
+		if i < len(via) {
+			stepTo = m.newValue(via[i]).Interface()
+		}
+
+		if err := m.mapValues(st, current, stepTo); err != nil {
+			return m.translateError(fmt.Errorf("%s -> %s: %w", elemType(current), elemType(stepTo), err))
+		}
+
+		current = stepTo
+	}
+
+	return nil
+}
+
+// elemType returns type of value pointed by v, or of v if it isn't a pointer.
+func elemType(v interface{}) reflect.Type {
+	tp := reflect.TypeOf(v)
+	if tp != nil && tp.Kind() == reflect.Ptr {
+		return tp.Elem()
+	}
+
+	return tp
+}
+
+// versionChain returns types of the shortest chain of declared pairs from from into to, excluding from.
+// Values of the same type are mapped directly.
+func (m *Mapper) versionChain(from, to reflect.Type) ([]reflect.Type, bool) {
+	if from == to {
+		return []reflect.Type{to}, true
+	}
+
+	m.mu.RLock()
+	next := make(map[reflect.Type][]reflect.Type, len(m.pairs))
+	for _, pair := range m.pairs {
+		next[pair.from] = append(next[pair.from], pair.to)
+	}
+	m.mu.RUnlock()
+
+	// previous types of chains found breadth first
+	previous := map[reflect.Type]reflect.Type{from: nil}
+	queue := []reflect.Type{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			break
+		}
+
+		for _, tp := range next[current] {
+			if _, ok := previous[tp]; !ok {
+				previous[tp] = current
+				queue = append(queue, tp)
+			}
+		}
+	}
+
+	if _, ok := previous[to]; !ok {
+		return nil, false
+	}
+
+	var chain []reflect.Type
+	for tp := to; tp != from; tp = previous[tp] {
+		chain = append([]reflect.Type{tp}, chain...)
+	}
+
+	return chain, true
+}
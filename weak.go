@@ -0,0 +1,99 @@
+package automapper
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// WithWeaklyTypedInput enables forgiving coercions for loosely-typed inputs, e.g. config or JSON blobs,
+// like mapstructure's weak mode. Values of types, which can't be mapped otherwise, are coerced:
+//   - strings and numbers into each other, see WithStringNumberConversions;
+//   - numbers into each other, see WithNumericConversion;
+//   - strings like "1" or "true" into bools, see strconv.ParseBool, and non-zero numbers into true;
+//   - bools into 1 or 0 numbers, and "1" or "0" strings;
+//   - elements of slices, and single values into slices of one element;
+//   - values held by interfaces by their dynamic types.
+//
+// Strings which can't be parsed fail with *ConversionError.
+func WithWeaklyTypedInput() Option {
+	return func(o *options) {
+		o.weak = true
+		o.stringNumbers = true
+		o.numeric = true
+	}
+}
+
+// mapWeak maps from into to with strategy detected for their types, coercing them otherwise.
+func (m *Mapper) mapWeak(st *mapState, from, to reflect.Value) error {
+	if from.Kind() == reflect.Interface {
+		if from.IsNil() {
+			return nil
+		}
+
+		from = from.Elem()
+	}
+
+	if mappingType := m.detectMappingType(from.Type(), to.Type()); mappingType != unsupported {
+		return m.strats[mappingType](st, from, to)
+	}
+
+	switch {
+	case st.opts.boolStrings != nil && isBoolString(from.Kind(), to.Kind()):
+		return st.opts.boolStrings.convert(from, to)
+	case to.Kind() == reflect.Bool && from.Kind() == reflect.String:
+		b, err := strconv.ParseBool(from.String())
+		if err != nil {
+			return &ConversionError{From: from.Type(), To: to.Type(), Value: from.String(), Err: err}
+		}
+
+		to.SetBool(b)
+	case to.Kind() == reflect.Bool && isNumeric(from.Kind()):
+		to.SetBool(!from.IsZero())
+	case from.Kind() == reflect.Bool && isNumeric(to.Kind()):
+		to.Set(reflect.ValueOf(boolInt(from.Bool())).Convert(to.Type()))
+	case from.Kind() == reflect.Bool && to.Kind() == reflect.String:
+		to.SetString(strconv.Itoa(boolInt(from.Bool())))
+	case isStringNumber(from.Kind(), to.Kind()):
+		return convertStringNumber(from, to)
+	case isNumeric(from.Kind()) && isNumeric(to.Kind()):
+		return convertNumeric(st, from, to)
+	case to.Kind() == reflect.Slice && (from.Kind() == reflect.Slice || from.Kind() == reflect.Array):
+		slice := reflect.MakeSlice(to.Type(), from.Len(), from.Len())
+		for i := 0; i < from.Len(); i++ {
+			err := m.mapWeak(st, from.Index(i), slice.Index(i))
+			if err != nil {
+				return withFieldPath(err, "["+strconv.Itoa(i)+"]")
+			}
+		}
+
+		to.Set(slice)
+	case to.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(to.Type(), 1, 1)
+		err := m.mapWeak(st, from, slice.Index(0))
+		if err != nil {
+			return withFieldPath(err, "[0]")
+		}
+
+		to.Set(slice)
+	case to.Kind() == reflect.Ptr && from.Kind() != reflect.Ptr:
+		elem := m.allocate(st, to.Type().Elem())
+		err := m.mapWeak(st, from, elem.Elem())
+		if err != nil {
+			return err
+		}
+
+		to.Set(elem)
+	default:
+		return m.missingConverter(from, to)
+	}
+
+	return nil
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}